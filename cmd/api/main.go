@@ -9,16 +9,26 @@ import (
 	"os"
 	"os/signal"
 	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/repository"
 	"spotify_recommender/internal/domain/service"
+	"spotify_recommender/internal/domain/valueObject"
 	"spotify_recommender/internal/infrastructure/cache"
+	"spotify_recommender/internal/infrastructure/calendar"
+	"spotify_recommender/internal/infrastructure/crypto"
 	"spotify_recommender/internal/infrastructure/database/postgres"
 	"spotify_recommender/internal/infrastructure/external/spotify"
 	"spotify_recommender/internal/infrastructure/external/weather"
+	"spotify_recommender/internal/infrastructure/external/weather/metno"
+	"spotify_recommender/internal/infrastructure/external/weather/openmeteo"
+	"spotify_recommender/internal/infrastructure/external/weather/openweathermap"
 	"spotify_recommender/internal/interface/http/handler"
 	"spotify_recommender/internal/interface/http/middleware"
 	http "spotify_recommender/internal/interface/http/router"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/jmoiron/sqlx"
@@ -37,32 +47,85 @@ func main() {
 	defer db.Close()
 
 	spotifyClient := setupSpotifyClient()
-	weatherClient := setupWeatherClient()
+	weatherReporter := setupWeatherCache(setupWeatherProviders())
+	holidayCalendar := setupCalendar()
 
 	userRepo := postgres.NewUserRepository(db)
 	trackRepo := postgres.NewTrackRepository(db)
 	playlistRepo := postgres.NewPlaylistRepository(db)
 	recommendationRepo := postgres.NewRecommendationRepository(db)
+	sessionRepo := postgres.NewSessionRepository(db)
+	libraryImportRepo := postgres.NewLibraryImportRepository(db)
+	listeningHistoryRepo := postgres.NewListeningHistoryRepository(db)
 
-	recommendationService := service.NewRecommendationService(userRepo, trackRepo, recommendationRepo)
-	playlistService := service.NewPlaylistService(playlistRepo, trackRepo, userRepo, recommendationRepo)
+	spotifyAccountRepo := setupSpotifyAccountRepository(db)
+	var spotifyPublisher service.SpotifyPlaylistPublisher
+	if spotifyAccountRepo != nil {
+		spotifyPublisher = spotify.NewPlaylistPublisher(spotifyClient, spotifyAccountRepo)
+	}
+
+	recommendationService := service.NewRecommendationService(
+		userRepo,
+		trackRepo,
+		recommendationRepo,
+		setupCandidateFallback(spotifyClient),
+		service.NewSeedSelector(userRepo, listeningHistoryRepo, trackRepo),
+		holidayCalendar,
+	)
+	playlistService := service.NewPlaylistService(
+		playlistRepo,
+		trackRepo,
+		userRepo,
+		recommendationRepo,
+		spotifyPublisher,
+	)
 
 	userManagementUseCase := usecase.NewUserManagementUseCase(userRepo)
-	getRecommendationsUseCase := usecase.NewGetRecommendationsUseCase(recommendationService, trackRepo, weatherClient)
+	getRecommendationsUseCase := usecase.NewGetRecommendationsUseCase(recommendationService, userRepo, trackRepo, weatherReporter, holidayCalendar)
 	savePlaylistUseCase := usecase.NewSavePlaylistUseCase(playlistService)
 	savePlaylistFromRecommendationUseCase := usecase.NewSavePlaylistFromRecommendationUseCase(playlistService)
+	spotifyAccountUseCase := usecase.NewSpotifyAccountUseCase(
+		spotifyClient,
+		spotifyAccountRepo,
+		userRepo,
+		spotify.CommonScopes(),
+	)
+	importSpotifyLibraryUseCase := usecase.NewImportSpotifyLibraryUseCase(
+		spotify.NewLibrary(spotifyClient, spotifyAccountRepo),
+		libraryImportRepo,
+		trackRepo,
+		playlistRepo,
+		userRepo,
+	)
+	syncSpotifyListeningUseCase := usecase.NewSyncSpotifyListeningUseCase(
+		spotify.NewListeningHistory(spotifyClient, spotifyAccountRepo),
+		listeningHistoryRepo,
+		spotifyAccountRepo,
+		trackRepo,
+	)
+	playbackUseCase := usecase.NewPlaybackUseCase(
+		spotify.NewPlayer(spotifyClient, spotifyAccountRepo),
+		recommendationService,
+		trackRepo,
+		userRepo,
+	)
 
-	jwtMiddleware := setupJWTMiddleware()
+	jwtMiddleware := setupJWTMiddleware(sessionRepo)
 
 	userHandler := handler.NewUserHandler(userManagementUseCase, jwtMiddleware)
-	recommendationHandler := handler.NewRecommendationHandler(getRecommendationsUseCase)
+	recommendationHandler := handler.NewRecommendationHandler(getRecommendationsUseCase, recommendationService)
 	playlistHandler := handler.NewPlaylistHandler(
 		savePlaylistUseCase,
 		savePlaylistFromRecommendationUseCase,
 		playlistService,
 	)
+	spotifyHandler := handler.NewSpotifyHandler(
+		spotifyAccountUseCase,
+		importSpotifyLibraryUseCase,
+		playbackUseCase,
+	)
 
-	r := http.router.Setup(userHandler, recommendationHandler, playlistHandler, jwtMiddleware)
+	r := http.Setup(userHandler, recommendationHandler, playlistHandler, spotifyHandler, jwtMiddleware)
 
 	server := &https.Server{
 		Addr:         fmt.Sprintf(":%s", getEnv("PORT", "8080")),
@@ -74,12 +137,18 @@ func main() {
 
 	serverCtx, serverStopCtx := context.WithCancel(context.Background())
 
+	if spotifyAccountRepo != nil {
+		go syncSpotifyListeningUseCase.Run(serverCtx, getEnvDuration("SPOTIFY_LISTENING_SYNC_INTERVAL", 30*time.Minute))
+	}
+	go runSessionCleanup(serverCtx, sessionRepo, getEnvDuration("SESSION_CLEANUP_INTERVAL", time.Hour))
+
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 	go func() {
 		<-sig
 
-		shutdownCtx, _ := context.WithTimeout(serverCtx, 30*time.Second)
+		shutdownCtx, cancelShutdown := context.WithTimeout(serverCtx, 30*time.Second)
+		defer cancelShutdown()
 
 		go func() {
 			<-shutdownCtx.Done()
@@ -125,32 +194,170 @@ func setupRedisCache() (*cache.RedisCache, error) {
 	return cache.NewRedisCache(redisURL)
 }
 
+// setupSpotifyAccountRepository stores linked Spotify accounts with their
+// tokens encrypted under SPOTIFY_TOKEN_ENCRYPTION_KEY. Without a key it
+// returns nil and Spotify account features are unavailable.
+func setupSpotifyAccountRepository(db *sqlx.DB) repository.SpotifyAccountRepository {
+	key := getEnv("SPOTIFY_TOKEN_ENCRYPTION_KEY", "")
+	if key == "" {
+		log.Println("Warning: SPOTIFY_TOKEN_ENCRYPTION_KEY is not set, Spotify account linking is disabled")
+		return nil
+	}
+
+	tokenCipher, err := crypto.NewCipherFromBase64(key)
+	if err != nil {
+		log.Fatalf("Failed to set up token encryption: %v", err)
+	}
+	return postgres.NewSpotifyAccountRepository(db, tokenCipher)
+}
+
 func setupSpotifyClient() *spotify.Client {
 	config := spotify.Config{
 		ClientID:     getEnv("SPOTIFY_CLIENT_ID", ""),
 		ClientSecret: getEnv("SPOTIFY_CLIENT_SECRET", ""),
-		RedirectURI:  getEnv("SPOTIFY_REDIRECT_URI", "http://localhost:8080/api/auth/spotify/callback"),
+		RedirectURI:  getEnv("SPOTIFY_REDIRECT_URI", "http://localhost:8080/api/v1/auth/spotify/callback"),
+		AccountsURL:  getEnv("SPOTIFY_ACCOUNTS_URL", spotify.DefaultAccountsURL),
+		APIURL:       getEnv("SPOTIFY_API_URL", spotify.DefaultAPIURL),
 	}
 
 	return spotify.NewClient(config)
 }
 
-func setupWeatherClient() *openweathermap.Client {
-	config := openweathermap.Config{
-		APIKey: getEnv("OPENWEATHERMAP_API_KEY", ""),
-		Units:  "metric",
+// setupWeatherProviders returns the weather providers named in
+// WEATHER_PROVIDERS, to be tried in that order. OpenWeatherMap is left out
+// unless an API key is configured.
+func setupWeatherProviders() *weather.Registry {
+	var providers []weather.Provider
+
+	for _, name := range strings.Split(getEnv("WEATHER_PROVIDERS", "openweathermap,open-meteo,met.no"), ",") {
+		switch name = strings.TrimSpace(name); name {
+		case openweathermap.ProviderName:
+			config := openweathermap.Config{
+				APIKey:  getEnv("OPENWEATHERMAP_API_KEY", ""),
+				Units:   getEnv("OPENWEATHERMAP_UNITS", openweathermap.DefaultUnits),
+				BaseURL: getEnv("OPENWEATHERMAP_API_URL", openweathermap.DefaultBaseURL),
+				Timeout: getEnvDuration("OPENWEATHERMAP_TIMEOUT", openweathermap.DefaultTimeout),
+			}
+			if config.APIKey == "" {
+				continue
+			}
+			providers = append(providers, weather.Provider{
+				Name:    name,
+				Service: openweathermap.NewClient(config),
+				Timeout: config.Timeout,
+			})
+		case openmeteo.ProviderName:
+			config := openmeteo.Config{
+				BaseURL: getEnv("OPEN_METEO_API_URL", openmeteo.DefaultBaseURL),
+				Timeout: getEnvDuration("OPEN_METEO_TIMEOUT", openmeteo.DefaultTimeout),
+			}
+			providers = append(providers, weather.Provider{
+				Name:    name,
+				Service: openmeteo.NewClient(config),
+				Timeout: config.Timeout,
+			})
+		case metno.ProviderName:
+			config := metno.Config{
+				BaseURL:   getEnv("MET_NO_API_URL", metno.DefaultBaseURL),
+				Timeout:   getEnvDuration("MET_NO_TIMEOUT", metno.DefaultTimeout),
+				UserAgent: getEnv("MET_NO_USER_AGENT", metno.DefaultUserAgent),
+			}
+			providers = append(providers, weather.Provider{
+				Name:    name,
+				Service: metno.NewClient(config),
+				Timeout: config.Timeout,
+			})
+		default:
+			log.Printf("Warning: unknown weather provider %q", name)
+		}
 	}
 
-	return openweathermap.NewClient(config)
+	health := weather.DefaultHealthConfig()
+	health.Cooldown = getEnvDuration("WEATHER_PROVIDER_COOLDOWN", health.Cooldown)
+
+	return weather.NewRegistry(health, providers...)
 }
 
-func setupJWTMiddleware() *middleware.JWTMiddleware {
+// setupWeatherCache caches the reports of reporter per geohash tile in the
+// backend named by WEATHER_CACHE: "memory", "redis" or "none".
+func setupWeatherCache(reporter usecase.WeatherReporter) usecase.WeatherReporter {
+	var backend cache.Cache
+	switch getEnv("WEATHER_CACHE", "memory") {
+	case "none":
+		return reporter
+	case "redis":
+		redisCache, err := setupRedisCache()
+		if err != nil {
+			log.Printf("Warning: weather cache falls back to memory: %v", err)
+			backend = cache.NewMemoryCache()
+		} else {
+			backend = redisCache
+		}
+	default:
+		backend = cache.NewMemoryCache()
+	}
+
+	config := weather.DefaultCacheConfig()
+	config.TTL = getEnvDuration("WEATHER_CACHE_TTL", config.TTL)
+	config.ForecastTTL = getEnvDuration("WEATHER_FORECAST_CACHE_TTL", config.ForecastTTL)
+	config.Precision = getEnvInt("WEATHER_CACHE_GEOHASH_PRECISION", config.Precision)
+
+	return weather.NewCachedReporter(reporter, backend, config)
+}
+
+// setupCalendar loads the weekend days and holidays from
+// HOLIDAY_CALENDAR_FILE, or uses a Saturday and Sunday weekend without
+// holidays when it is unset.
+func setupCalendar() *valueObject.Calendar {
+	path := getEnv("HOLIDAY_CALENDAR_FILE", "")
+	if path == "" {
+		return valueObject.DefaultCalendar()
+	}
+
+	holidayCalendar, err := calendar.Load(path)
+	if err != nil {
+		log.Fatalf("Failed to load holiday calendar: %v", err)
+	}
+	return holidayCalendar
+}
+
+// setupCandidateFallback returns Spotify as the fallback source of
+// recommendation candidates, unless SPOTIFY_CANDIDATE_FALLBACK is "false" or
+// no Spotify credentials are configured.
+func setupCandidateFallback(spotifyClient *spotify.Client) service.CandidateSource {
+	if getEnv("SPOTIFY_CANDIDATE_FALLBACK", "true") == "false" || getEnv("SPOTIFY_CLIENT_ID", "") == "" {
+		return nil
+	}
+	return spotify.NewCandidateSource(spotifyClient)
+}
+
+// runSessionCleanup deletes expired sessions and refresh tokens every
+// interval until ctx is done. Revoked sessions go once they expire.
+func runSessionCleanup(ctx context.Context, sessionRepo repository.SessionRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := sessionRepo.DeleteExpired(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to delete expired sessions: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func setupJWTMiddleware(sessionRepo repository.SessionRepository) *middleware.JWTMiddleware {
 	config := middleware.JWTConfig{
-		SecretKey:     getEnv("JWT_SECRET_KEY", "supersecretkey"),
-		TokenDuration: 24 * time.Hour, // 24 hours
+		SecretKey:            getEnv("JWT_SECRET_KEY", "supersecretkey"),
+		TokenDuration:        15 * time.Minute,
+		RefreshTokenDuration: 30 * 24 * time.Hour, // 30 days
 	}
 
-	return middleware.NewJWTMiddleware(config)
+	return middleware.NewJWTMiddleware(config, sessionRepo)
 }
 
 func getEnv(key, defaultValue string) string {
@@ -160,3 +367,19 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,26 @@
+package dto
+
+type SpotifyDeviceDTO struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	IsActive      bool   `json:"is_active"`
+	IsRestricted  bool   `json:"is_restricted"`
+	VolumePercent int    `json:"volume_percent"`
+}
+
+type PlaybackStateDTO struct {
+	IsPlaying  bool              `json:"is_playing"`
+	ProgressMS int64             `json:"progress_ms"`
+	Device     *SpotifyDeviceDTO `json:"device,omitempty"`
+	Track      *TrackDTO         `json:"track,omitempty"`
+}
+
+type PlayRecommendationDTO struct {
+	DeviceID string `json:"device_id"`
+}
+
+type QueueTracksDTO struct {
+	DeviceID string   `json:"device_id"`
+	TrackIDs []string `json:"track_ids" binding:"required,min=1,max=50"`
+}
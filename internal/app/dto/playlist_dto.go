@@ -7,17 +7,18 @@ import (
 )
 
 type PlaylistDTO struct {
-	ID          string     `json:"id"`
-	UserID      string     `json:"user_id"`
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	Mood        string     `json:"mood"`
-	Weather     string     `json:"weather,omitempty"`
-	TimeOfDay   string     `json:"time_of_day,omitempty"`
-	Tracks      []TrackDTO `json:"tracks,omitempty"`
-	IsPublic    bool       `json:"is_public"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID                string     `json:"id"`
+	UserID            string     `json:"user_id"`
+	Name              string     `json:"name"`
+	Description       string     `json:"description"`
+	Mood              string     `json:"mood"`
+	Weather           string     `json:"weather,omitempty"`
+	TimeOfDay         string     `json:"time_of_day,omitempty"`
+	Tracks            []TrackDTO `json:"tracks,omitempty"`
+	IsPublic          bool       `json:"is_public"`
+	SpotifyPlaylistID string     `json:"spotify_playlist_id,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
 type CreatePlaylistDTO struct {
@@ -34,36 +35,51 @@ func PlaylistFromEntity(playlist *entity.Playlist, tracks []*entity.Track) Playl
 	}
 
 	return PlaylistDTO{
-		ID:          playlist.ID,
-		UserID:      playlist.UserID,
-		Name:        playlist.Name,
-		Description: playlist.Description,
-		Mood:        string(playlist.Mood),
-		Weather:     string(playlist.Weather),
-		TimeOfDay:   string(playlist.TimeOfDay),
-		Tracks:      trackDTOs,
-		IsPublic:    playlist.IsPublic,
-		CreatedAt:   playlist.CreatedAt,
-		UpdatedAt:   playlist.UpdatedAt,
+		ID:                playlist.ID,
+		UserID:            playlist.UserID,
+		Name:              playlist.Name,
+		Description:       playlist.Description,
+		Mood:              string(playlist.Mood),
+		Weather:           string(playlist.Weather),
+		TimeOfDay:         string(playlist.TimeOfDay),
+		Tracks:            trackDTOs,
+		IsPublic:          playlist.IsPublic,
+		SpotifyPlaylistID: playlist.SpotifyPlaylistID,
+		CreatedAt:         playlist.CreatedAt,
+		UpdatedAt:         playlist.UpdatedAt,
 	}
 }
 
 func (dto CreatePlaylistDTO) ToEntity(userID string) *entity.Playlist {
-	mood := valueObject.Mood(dto.Mood)
-	if !valueObject.ValidMood(mood) {
-		mood = valueObject.MoodHappy
-	}
-
-	playlist := entity.NewPlaylist(userID, dto.Name, dto.Description, mood)
+	playlist := entity.NewPlaylist(userID, dto.Name, dto.Description, valueObject.Mood(dto.Mood))
 	playlist.IsPublic = dto.IsPublic
 
 	return playlist
 }
 
-func PlaylistsFromEntities(playlists []*entity.Playlist) []PlaylistDTO {
+// PlaylistsFromEntities converts the playlists along with their tracks, keyed
+// by playlist ID.
+func PlaylistsFromEntities(playlists []*entity.Playlist, tracks map[string][]*entity.Track) []PlaylistDTO {
 	result := make([]PlaylistDTO, len(playlists))
 	for i, playlist := range playlists {
-		result[i] = PlaylistFromEntity(playlist, nil)
+		result[i] = PlaylistFromEntity(playlist, tracks[playlist.ID])
 	}
 	return result
 }
+
+type CreatePlaylistFromRecommendationDTO struct {
+	RecommendationID string `json:"recommendation_id" binding:"required"`
+	Name             string `json:"name" binding:"required"`
+	Description      string `json:"description"`
+}
+
+type UpdatePlaylistDTO struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Mood        string `json:"mood" binding:"required"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+type AddTrackDTO struct {
+	TrackID string `json:"track_id" binding:"required"`
+}
@@ -2,24 +2,46 @@ package dto
 
 import (
 	"spotify_recommender/internal/domain/entity"
-	"spotify_recommender/internal/domain/valueObject"
 	"time"
 )
 
+// RecommendationDTO is a recommendation with its tracks. WeatherSource names
+// the weather provider that determined Weather, or is "request" or
+// "fallback"; it is only set on newly requested recommendations.
 type RecommendationDTO struct {
-	ID        string     `json:"id"`
-	UserID    string     `json:"user_id"`
-	Mood      string     `json:"mood"`
-	Weather   string     `json:"weather"`
-	TimeOfDay string     `json:"time_of_day"`
-	Tracks    []TrackDTO `json:"tracks"`
-	CreatedAt time.Time  `json:"created_at"`
+	ID            string     `json:"id"`
+	UserID        string     `json:"user_id"`
+	Mood          string     `json:"mood"`
+	Weather       string     `json:"weather"`
+	WeatherSource string     `json:"weather_source,omitempty"`
+	TimeOfDay     string     `json:"time_of_day"`
+	DayPart       string     `json:"day_part,omitempty"`
+	DayType       string     `json:"day_type,omitempty"`
+	Tracks        []TrackDTO `json:"tracks"`
+	CreatedAt     time.Time  `json:"created_at"`
 }
 
+// RecommendationRequestDTO asks for a recommendation for the weather and
+// time of day at Location at the moment At, by default now. Weather,
+// TimeOfDay, DayPart and DayType, when set, take precedence over the ones
+// derived from them.
+// Timezone and TimeOfDayMode override the user's own for deriving the time
+// of day.
 type RecommendationRequestDTO struct {
-	Mood      string `json:"mood" binding:"required"`
-	Weather   string `json:"weather"`
-	TimeOfDay string `json:"time_of_day"`
+	Mood          string       `json:"mood" binding:"required"`
+	Weather       string       `json:"weather"`
+	TimeOfDay     string       `json:"time_of_day"`
+	DayPart       string       `json:"day_part,omitempty"`
+	DayType       string       `json:"day_type,omitempty"`
+	At            *time.Time   `json:"at,omitempty"`
+	Location      *LocationDTO `json:"location,omitempty"`
+	Timezone      string       `json:"timezone,omitempty" binding:"omitempty,timezone"`
+	TimeOfDayMode string       `json:"time_of_day_mode,omitempty" binding:"omitempty,oneof=clock astronomical"`
+}
+
+type LocationDTO struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
 }
 
 func RecommendationFromEntity(rec *entity.Recommendation, tracks []*entity.Track) RecommendationDTO {
@@ -31,33 +53,13 @@ func RecommendationFromEntity(rec *entity.Recommendation, tracks []*entity.Track
 		Mood:      string(rec.Mood),
 		Weather:   string(rec.Weather),
 		TimeOfDay: string(rec.TimeOfDay),
+		DayPart:   string(rec.DayPart),
+		DayType:   string(rec.DayType),
 		Tracks:    trackDTOs,
 		CreatedAt: rec.CreatedAt,
 	}
 }
 
-func (dto RecommendationRequestDTO) ToEntity(userID string) *entity.Recommendation {
-	mood := valueObject.Mood(dto.Mood)
-	weather := valueObject.Weather(dto.Weather)
-	timeOfDay := valueObject.TimeOfDay(dto.TimeOfDay)
-
-	if !valueObject.ValidMood(mood) {
-		mood = valueObject.MoodHappy
-	}
-
-	if !valueObject.ValidWeather(weather) {
-		weather = valueObject.WeatherSunny
-	}
-
-	if dto.TimeOfDay == "" {
-		timeOfDay = valueObject.GetCurrentTimeOfToday()
-	}
-
-	return entity.NewRecommendation(
-		userID,
-		mood,
-		weather,
-		timeOfDay,
-		[]string{},
-	)
+type TrackFeedbackDTO struct {
+	Liked *bool `json:"liked" binding:"required"`
 }
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"spotify_recommender/internal/domain/entity"
+	"time"
+)
+
+type LibraryImportDTO struct {
+	ID                string     `json:"id"`
+	Status            string     `json:"status"`
+	Phase             string     `json:"phase"`
+	SavedTracksDone   int        `json:"saved_tracks_done"`
+	SavedTracksTotal  int        `json:"saved_tracks_total"`
+	PlaylistsDone     int        `json:"playlists_done"`
+	PlaylistsTotal    int        `json:"playlists_total"`
+	TracksImported    int        `json:"tracks_imported"`
+	PlaylistsImported int        `json:"playlists_imported"`
+	Error             string     `json:"error,omitempty"`
+	StartedAt         time.Time  `json:"started_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+}
+
+func LibraryImportFromEntity(libraryImport *entity.LibraryImport) LibraryImportDTO {
+	result := LibraryImportDTO{
+		ID:                libraryImport.ID,
+		Status:            string(libraryImport.Status),
+		Phase:             string(libraryImport.Phase),
+		SavedTracksDone:   min(libraryImport.SavedTracksOffset, libraryImport.SavedTracksTotal),
+		SavedTracksTotal:  libraryImport.SavedTracksTotal,
+		PlaylistsDone:     min(libraryImport.PlaylistsOffset, libraryImport.PlaylistsTotal),
+		PlaylistsTotal:    libraryImport.PlaylistsTotal,
+		TracksImported:    libraryImport.TracksImported,
+		PlaylistsImported: libraryImport.PlaylistsImported,
+		Error:             libraryImport.Error,
+		StartedAt:         libraryImport.StartedAt,
+		UpdatedAt:         libraryImport.UpdatedAt,
+	}
+	if !libraryImport.CompletedAt.IsZero() {
+		result.CompletedAt = &libraryImport.CompletedAt
+	}
+	return result
+}
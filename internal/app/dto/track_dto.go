@@ -7,16 +7,28 @@ import (
 )
 
 type TrackDTO struct {
-	ID            string           `json:"id"`
-	SpotifyID     string           `json:"spotify_id"`
-	Name          string           `json:"name"`
-	Artist        string           `json:"artist"`
-	Album         string           `json:"album"`
-	ReleaseDate   time.Time        `json:"release_date"`
-	Popularity    int              `json:"popularity"`
-	AudioFeatures AudioFeaturesDTO `json:"audio_features"`
-	PreviewURL    string           `json:"preview_url"`
-	ImageURL      string           `json:"image_url"`
+	ID                   string           `json:"id"`
+	SpotifyID            string           `json:"spotify_id"`
+	Name                 string           `json:"name"`
+	Artist               string           `json:"artist"`
+	Artists              []TrackArtistDTO `json:"artists"`
+	Genres               []string         `json:"genres"`
+	Album                string           `json:"album"`
+	ReleaseDate          time.Time        `json:"release_date"`
+	ReleaseDatePrecision string           `json:"release_date_precision"`
+	ISRC                 string           `json:"isrc,omitempty"`
+	Explicit             bool             `json:"explicit"`
+	DurationMS           int              `json:"duration_ms"`
+	Popularity           int              `json:"popularity"`
+	AudioFeatures        AudioFeaturesDTO `json:"audio_features"`
+	PreviewURL           string           `json:"preview_url"`
+	ExternalURL          string           `json:"external_url"`
+	ImageURL             string           `json:"image_url"`
+}
+
+type TrackArtistDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 type AudioFeaturesDTO struct {
@@ -36,14 +48,29 @@ type AudioFeaturesDTO struct {
 }
 
 func TrackFromEntity(track *entity.Track) TrackDTO {
+	artists := make([]TrackArtistDTO, len(track.Artists))
+	for i, artist := range track.Artists {
+		artists[i] = TrackArtistDTO{ID: artist.ID, Name: artist.Name}
+	}
+	genres := track.Genres
+	if genres == nil {
+		genres = []string{}
+	}
+
 	return TrackDTO{
-		ID:          track.ID,
-		SpotifyID:   track.SpotifyID,
-		Name:        track.Name,
-		Artist:      track.Artist,
-		Album:       track.Album,
-		ReleaseDate: track.ReleaseDate,
-		Popularity:  track.Popularity,
+		ID:                   track.ID,
+		SpotifyID:            track.SpotifyID,
+		Name:                 track.Name,
+		Artist:               track.Artist,
+		Artists:              artists,
+		Genres:               genres,
+		Album:                track.Album,
+		ReleaseDate:          track.ReleaseDate,
+		ReleaseDatePrecision: string(track.ReleaseDatePrecision),
+		ISRC:                 track.ISRC,
+		Explicit:             track.Explicit,
+		DurationMS:           track.DurationMS,
+		Popularity:           track.Popularity,
 		AudioFeatures: AudioFeaturesDTO{
 			Danceability:     track.AudioFeatures.Danceability,
 			Energy:           track.AudioFeatures.Energy,
@@ -59,13 +86,14 @@ func TrackFromEntity(track *entity.Track) TrackDTO {
 			Duration:         track.AudioFeatures.Duration,
 			TimeSignature:    track.AudioFeatures.TimeSignature,
 		},
-		PreviewURL: track.PreviewURL,
-		ImageURL:   track.ImageURL,
+		PreviewURL:  track.PreviewURL,
+		ExternalURL: track.ExternalURL,
+		ImageURL:    track.ImageURL,
 	}
 }
 
 func (dto TrackDTO) ToEntity() *entity.Track {
-	return entity.NewTrack(
+	track := entity.NewTrack(
 		dto.SpotifyID,
 		dto.Name,
 		dto.Artist,
@@ -90,6 +118,24 @@ func (dto TrackDTO) ToEntity() *entity.Track {
 		dto.PreviewURL,
 		dto.ImageURL,
 	)
+
+	if len(dto.Artists) > 0 {
+		artists := make([]entity.TrackArtist, len(dto.Artists))
+		for i, artist := range dto.Artists {
+			artists[i] = entity.TrackArtist{ID: artist.ID, Name: artist.Name}
+		}
+		track.SetArtists(artists)
+	}
+	track.Genres = dto.Genres
+	if dto.ReleaseDatePrecision != "" {
+		track.ReleaseDatePrecision = entity.ReleaseDatePrecision(dto.ReleaseDatePrecision)
+	}
+	track.ISRC = dto.ISRC
+	track.Explicit = dto.Explicit
+	track.DurationMS = dto.DurationMS
+	track.ExternalURL = dto.ExternalURL
+
+	return track
 }
 
 func TracksFromEntities(tracks []*entity.Track) []TrackDTO {
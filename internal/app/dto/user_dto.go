@@ -10,6 +10,7 @@ type UserDTO struct {
 	Email       string         `json:"email"`
 	Name        string         `json:"name"`
 	SpotifyID   string         `json:"spotify_id,omitempty"`
+	Timezone    string         `json:"timezone,omitempty"`
 	Preferences PreferencesDTO `json:"preferences"`
 	LastLoginAt time.Time      `json:"last_login_at"`
 	CreatedAt   time.Time      `json:"created_at"`
@@ -21,6 +22,7 @@ type PreferencesDTO struct {
 	MinTempo       float64  `json:"min_tempo"`
 	MaxTempo       float64  `json:"max_tempo"`
 	PreferredMoods []string `json:"preferred_moods"`
+	TimeOfDayMode  string   `json:"time_of_day_mode,omitempty" binding:"omitempty,oneof=clock astronomical"`
 }
 
 func UserFromEntity(user *entity.User) UserDTO {
@@ -29,12 +31,14 @@ func UserFromEntity(user *entity.User) UserDTO {
 		Email:     user.Email,
 		Name:      user.Name,
 		SpotifyID: user.SpotifyID,
+		Timezone:  user.Timezone,
 		Preferences: PreferencesDTO{
 			FavoriteGenres: user.Preferences.FavoriteGenres,
 			DislikedGenres: user.Preferences.DislikedGenres,
 			MinTempo:       user.Preferences.MinTempo,
 			MaxTempo:       user.Preferences.MaxTempo,
 			PreferredMoods: user.Preferences.PreferredMoods,
+			TimeOfDayMode:  user.Preferences.TimeOfDayMode,
 		},
 		LastLoginAt: user.LastLoginAt,
 		CreatedAt:   user.CreatedAt,
@@ -45,12 +49,14 @@ func (dto UserDTO) ToEntity() *entity.User {
 	user := entity.NewUser(dto.Email, "", dto.Name)
 	user.ID = dto.ID
 	user.SpotifyID = dto.SpotifyID
+	user.Timezone = dto.Timezone
 	user.Preferences = entity.Preferences{
 		FavoriteGenres: dto.Preferences.FavoriteGenres,
 		DislikedGenres: dto.Preferences.DislikedGenres,
 		MinTempo:       dto.Preferences.MinTempo,
 		MaxTempo:       dto.Preferences.MaxTempo,
 		PreferredMoods: dto.Preferences.PreferredMoods,
+		TimeOfDayMode:  dto.Preferences.TimeOfDayMode,
 	}
 	user.LastLoginAt = dto.LastLoginAt
 	user.CreatedAt = dto.CreatedAt
@@ -69,13 +75,43 @@ type CreateUserDTO struct {
 	Email       string         `json:"email" binding:"required,email"`
 	Password    string         `json:"password" binding:"required,min=8"`
 	Name        string         `json:"name" binding:"required"`
+	Timezone    string         `json:"timezone" binding:"omitempty,timezone"`
 	Preferences PreferencesDTO `json:"preferences"`
 }
 
 type UpdatePreferencesDTO struct {
 	FavoriteGenres []string `json:"favorite_genres"`
 	DislikedGenres []string `json:"disliked_genres"`
-	MinTempo       float64  `json:"min_tempo"`
-	MaxTempo       float64  `json:"max_tempo"`
+	MinTempo       float64  `json:"min_tempo" binding:"gte=0"`
+	MaxTempo       float64  `json:"max_tempo" binding:"gtefield=MinTempo,lte=300"`
 	PreferredMoods []string `json:"preferred_moods"`
+	TimeOfDayMode  string   `json:"time_of_day_mode" binding:"omitempty,oneof=clock astronomical"`
+}
+
+// UpdateTimezoneDTO sets the time zone recommendations are made in, an IANA
+// name such as "Asia/Tokyo".
+type UpdateTimezoneDTO struct {
+	Timezone string `json:"timezone" binding:"required,timezone"`
+}
+
+type LoginDTO struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshTokenDTO struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type AuthResponseDTO struct {
+	AccessToken  string   `json:"access_token"`
+	RefreshToken string   `json:"refresh_token"`
+	TokenType    string   `json:"token_type"`
+	ExpiresIn    int      `json:"expires_in"`
+	User         *UserDTO `json:"user,omitempty"`
+}
+
+type ChangePasswordDTO struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
 }
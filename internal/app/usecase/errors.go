@@ -0,0 +1,24 @@
+package usecase
+
+import "spotify_recommender/internal/domain/domainerr"
+
+var (
+	ErrInvalidWeather = domainerr.Validation("invalid_weather", "invalid weather value",
+		domainerr.FieldError{Field: "weather", Message: "must be one of the supported weather conditions"})
+	ErrInvalidTimeOfDay = domainerr.Validation("invalid_time_of_day", "invalid time of day value",
+		domainerr.FieldError{Field: "time_of_day", Message: "must be one of morning, afternoon, evening, night"})
+	ErrInvalidDayPart = domainerr.Validation("invalid_day_part", "invalid day part value",
+		domainerr.FieldError{Field: "day_part", Message: "must be one of dawn, morning_commute, work_hours, evening_commute, morning, afternoon, evening, late_night"})
+	ErrInvalidDayType = domainerr.Validation("invalid_day_type", "invalid day type value",
+		domainerr.FieldError{Field: "day_type", Message: "must be one of workday, weekend, holiday"})
+	ErrInvalidTargetTime = domainerr.Validation("invalid_target_time", "invalid target time",
+		domainerr.FieldError{Field: "at", Message: "must be between an hour ago and 16 days ahead"})
+	ErrInvalidTimezone = domainerr.Validation("invalid_timezone", "invalid time zone",
+		domainerr.FieldError{Field: "timezone", Message: "must be an IANA time zone name such as Europe/Berlin"})
+	ErrInvalidTimeOfDayMode = domainerr.Validation("invalid_time_of_day_mode", "invalid time of day mode",
+		domainerr.FieldError{Field: "time_of_day_mode", Message: "must be one of clock, astronomical"})
+	ErrEmailTaken         = domainerr.Conflict("email_taken", "user with this email already exists")
+	ErrInvalidCredentials = domainerr.Unauthorized("invalid_credentials", "invalid email or password")
+	ErrWrongPassword      = domainerr.Validation("wrong_password", "current password is incorrect",
+		domainerr.FieldError{Field: "current_password", Message: "does not match"})
+)
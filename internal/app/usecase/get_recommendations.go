@@ -2,65 +2,201 @@ package usecase
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"spotify_recommender/internal/app/dto"
 	"spotify_recommender/internal/domain/entity"
 	"spotify_recommender/internal/domain/repository"
 	"spotify_recommender/internal/domain/service"
 	"spotify_recommender/internal/domain/valueObject"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Weather sources reported alongside a recommendation when no weather
+// provider determined the weather.
+const (
+	WeatherSourceRequest  = "request"
+	WeatherSourceFallback = "fallback"
+)
+
+const (
+	// maxForecastStep is the longest step between forecasts we expect from a
+	// provider; a forecast does not cover times further past it.
+	maxForecastStep = 6 * time.Hour
+	// maxForecastAhead is how far ahead recommendations can be requested,
+	// the horizon of the longest forecasts we use.
+	maxForecastAhead = 16 * 24 * time.Hour
+	// currentWeatherWindow is how far from now a target time may be for the
+	// current weather to stand in for a forecast.
+	currentWeatherWindow = time.Hour
 )
 
 type GetRecommendations struct {
 	recommendationService *service.RecommendationService
+	userRepository        repository.UserRepository
 	trackRepository       repository.TrackRepository
-	weatherService        WeatherService
+	weatherReporter       WeatherReporter
+	calendar              *valueObject.Calendar
 }
 
+// WeatherService is a provider of current weather, such as a weather API.
 type WeatherService interface {
 	GetCurrentWeather(ctx context.Context, latitude, longitude float64) (valueObject.Weather, error)
 }
 
+// WeatherReport is the weather at a place and the name of the provider that
+// determined it.
+type WeatherReport struct {
+	Weather valueObject.Weather
+	Source  string
+}
+
+// WeatherForecast is the weather expected at a place from Time until the
+// next forecast step.
+type WeatherForecast struct {
+	Time    time.Time
+	Weather valueObject.Weather
+}
+
+// ForecastService is a provider of weather forecasts. Forecasts are hourly
+// where the provider has them and in chronological order.
+type ForecastService interface {
+	GetHourlyForecast(ctx context.Context, latitude, longitude float64) ([]WeatherForecast, error)
+}
+
+// ForecastReport is the forecast for a place and the name of the provider
+// that made it.
+type ForecastReport struct {
+	Forecasts []WeatherForecast
+	Source    string
+}
+
+// WeatherAt returns the forecast weather at t. It reports false when t is
+// outside the forecast.
+func (r ForecastReport) WeatherAt(t time.Time) (valueObject.Weather, bool) {
+	for i := len(r.Forecasts) - 1; i >= 0; i-- {
+		forecast := r.Forecasts[i]
+		if forecast.Time.After(t) {
+			continue
+		}
+		if t.Sub(forecast.Time) >= maxForecastStep {
+			return "", false
+		}
+		return forecast.Weather, true
+	}
+	return "", false
+}
+
+// WeatherReporter looks up current weather and forecasts, typically by
+// trying several providers in turn, and reports which one answered.
+type WeatherReporter interface {
+	ReportCurrentWeather(ctx context.Context, latitude, longitude float64) (WeatherReport, error)
+	ReportHourlyForecast(ctx context.Context, latitude, longitude float64) (ForecastReport, error)
+}
+
 func NewGetRecommendationsUseCase(
 	recommendationService *service.RecommendationService,
+	userRepository repository.UserRepository,
 	trackRepository repository.TrackRepository,
-	weatherService WeatherService,
+	weatherReporter WeatherReporter,
+	calendar *valueObject.Calendar,
 ) *GetRecommendations {
+	if calendar == nil {
+		calendar = valueObject.DefaultCalendar()
+	}
+
 	return &GetRecommendations{
 		recommendationService: recommendationService,
+		userRepository:        userRepository,
 		trackRepository:       trackRepository,
-		weatherService:        weatherService,
+		weatherReporter:       weatherReporter,
+		calendar:              calendar,
 	}
 }
 
 func (uc *GetRecommendations) Execute(ctx context.Context,
 	userID string,
-	request dto.RecommendationDTO,
-	lat, lon float64) (*dto.RecommendationDTO, error) {
+	request dto.RecommendationRequestDTO) (*dto.RecommendationDTO, error) {
 	mood := valueObject.Mood(request.Mood)
 
 	if !valueObject.ValidMood(mood) {
-		return nil, errors.New("invalid mood value")
+		return nil, service.ErrInvalidMood
 	}
 
-	var weather valueObject.Weather
-	if request.Weather == "" {
-		var err error
-		weather, err = uc.weatherService.GetCurrentWeather(ctx, lat, lon)
-		if err != nil {
-			weather = valueObject.WeatherSunny
+	user, err := uc.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+
+	now := time.Now()
+	at := now
+	if request.At != nil {
+		at = *request.At
+		if at.Before(now.Add(-currentWeatherWindow)) || at.After(now.Add(maxForecastAhead)) {
+			return nil, ErrInvalidTargetTime
 		}
-	} else {
-		weather = valueObject.Weather(request.Weather)
-		if !valueObject.ValidWeather(weather) {
-			return nil, errors.New("invalid weather value")
+	}
+	at, err = localTime(at, request, user)
+	if err != nil {
+		return nil, err
+	}
+
+	weather, weatherSource, err := uc.resolveWeather(ctx, userID, request, at)
+	if err != nil {
+		return nil, err
+	}
+
+	temporal, err := uc.temporalContext(at, request, user)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendation, err := uc.recommendationService.GetRecommendationsByContext(
+		ctx, userID, mood, weather, temporal,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := uc.loadTracks(ctx, recommendation)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendationDTO := dto.RecommendationFromEntity(recommendation, tracks[0])
+	recommendationDTO.WeatherSource = weatherSource
+
+	return &recommendationDTO, nil
+
+}
+
+// temporalContext derives the time of day, day part and day type of the
+// local time at from the calendar, letting the request override each.
+// Overriding the time of day or day type moves the day part along with it.
+func (uc *GetRecommendations) temporalContext(
+	at time.Time,
+	request dto.RecommendationRequestDTO,
+	user *entity.User,
+) (valueObject.TemporalContext, error) {
+	temporal := uc.calendar.TemporalContext(at)
+
+	if request.DayType != "" {
+		temporal.DayType = valueObject.DayType(request.DayType)
+		if !valueObject.ValidDayType(temporal.DayType) {
+			return valueObject.TemporalContext{}, ErrInvalidDayType
 		}
+		temporal.DayPart = valueObject.DayPartAt(at, temporal.DayType)
 	}
 
-	var timeOfDay valueObject.TimeOfDay
 	if request.TimeOfDay == "" {
-		timeOfDay = valueObject.GetCurrentTimeOfToday()
+		timeOfDay, err := timeOfDayAt(at, request, user)
+		if err != nil {
+			return valueObject.TemporalContext{}, err
+		}
+		temporal.TimeOfDay = timeOfDay
 	} else {
-		timeOfDay = valueObject.TimeOfDay(request.TimeOfDay)
+		timeOfDay := valueObject.TimeOfDay(request.TimeOfDay)
 		isValid := false
 		for _, t := range valueObject.AllTimesOfDay() {
 			if timeOfDay == t {
@@ -69,28 +205,176 @@ func (uc *GetRecommendations) Execute(ctx context.Context,
 			}
 		}
 		if !isValid {
-			return nil, errors.New("invalid time of day value")
+			return valueObject.TemporalContext{}, ErrInvalidTimeOfDay
 		}
+		temporal.TimeOfDay = timeOfDay
+		temporal.DayPart = valueObject.DayPartFor(timeOfDay, temporal.DayType)
 	}
 
-	recommendation, err := uc.recommendationService.GetRecommendationsByContext(
-		ctx, userID, mood, weather, timeOfDay,
-	)
-	if err != nil {
-		return nil, err
+	if request.DayPart != "" {
+		temporal.DayPart = valueObject.DayPart(request.DayPart)
+		if !valueObject.ValidDayPart(temporal.DayPart) {
+			return valueObject.TemporalContext{}, ErrInvalidDayPart
+		}
 	}
 
-	var tracks []*entity.Track
-	for _, trackID := range recommendation.TrackIDs {
-		track, err := uc.trackRepository.GetByID(ctx, trackID)
+	return temporal, nil
+}
+
+// localTime returns at in the time zone of the request or, failing that, of
+// the user. Without either, at keeps its own zone: the offset it was given
+// with, or the server's for now.
+func localTime(at time.Time, request dto.RecommendationRequestDTO, user *entity.User) (time.Time, error) {
+	if request.Timezone != "" {
+		location, err := time.LoadLocation(request.Timezone)
 		if err != nil {
-			continue
+			return time.Time{}, ErrInvalidTimezone
+		}
+		return at.In(location), nil
+	}
+
+	if location, ok := user.Location(); ok {
+		return at.In(location), nil
+	}
+	return at, nil
+}
+
+// timeOfDayAt derives the time of day at the local time at, in the mode of
+// the request or, failing that, the user's preferred one. The astronomical
+// mode needs the location and falls back to the clock without it.
+func timeOfDayAt(at time.Time, request dto.RecommendationRequestDTO, user *entity.User) (valueObject.TimeOfDay, error) {
+	mode := valueObject.TimeOfDayMode(request.TimeOfDayMode)
+	if mode != "" && !valueObject.ValidTimeOfDayMode(mode) {
+		return "", ErrInvalidTimeOfDayMode
+	}
+	if mode == "" {
+		mode = valueObject.TimeOfDayMode(user.Preferences.TimeOfDayMode)
+	}
+
+	if mode == valueObject.TimeOfDayModeAstronomical && request.Location != nil {
+		return valueObject.AstronomicalTimeOfDayAt(at, request.Location.Latitude, request.Location.Longitude), nil
+	}
+	return valueObject.TimeOfDayAt(at), nil
+}
+
+// resolveWeather returns the weather requested or, failing that, the weather
+// at the requested location and time: the current weather when at is close
+// to now, the forecast weather otherwise. Without a location,
+// or when no provider answers, it falls back to sunny.
+func (uc *GetRecommendations) resolveWeather(ctx context.Context,
+	userID string,
+	request dto.RecommendationRequestDTO,
+	at time.Time) (valueObject.Weather, string, error) {
+	if request.Weather != "" {
+		weather := valueObject.Weather(request.Weather)
+		if !valueObject.ValidWeather(weather) {
+			return "", "", ErrInvalidWeather
+		}
+		return weather, WeatherSourceRequest, nil
+	}
+
+	if request.Location == nil {
+		return valueObject.WeatherSunny, WeatherSourceFallback, nil
+	}
+	lat, lon := request.Location.Latitude, request.Location.Longitude
+
+	if time.Until(at) < currentWeatherWindow {
+		report, err := uc.weatherReporter.ReportCurrentWeather(ctx, lat, lon)
+		if err != nil {
+			log.Warn().Err(err).Str("user_id", userID).Msg("failed to determine current weather, using fallback")
+			return valueObject.WeatherSunny, WeatherSourceFallback, nil
 		}
-		tracks = append(tracks, track)
+		return report.Weather, report.Source, nil
 	}
 
-	recommendationDTO := dto.RecommendationFromEntity(recommendation, tracks)
+	report, err := uc.weatherReporter.ReportHourlyForecast(ctx, lat, lon)
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Msg("failed to get weather forecast, using fallback")
+		return valueObject.WeatherSunny, WeatherSourceFallback, nil
+	}
+
+	weather, ok := report.WeatherAt(at)
+	if !ok {
+		log.Warn().Str("user_id", userID).Str("source", report.Source).Time("at", at).
+			Msg("weather forecast does not reach the target time, using fallback")
+		return valueObject.WeatherSunny, WeatherSourceFallback, nil
+	}
+	return weather, report.Source, nil
+}
+
+func (uc *GetRecommendations) GetByID(ctx context.Context,
+	userID string,
+	recommendationID string) (*dto.RecommendationDTO, error) {
+	recommendation, err := uc.recommendationService.GetRecommendation(ctx, userID, recommendationID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := uc.loadTracks(ctx, recommendation)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendationDTO := dto.RecommendationFromEntity(recommendation, tracks[0])
 
 	return &recommendationDTO, nil
+}
+
+func (uc *GetRecommendations) GetHistory(ctx context.Context, userID string) ([]dto.RecommendationDTO, error) {
+	recommendations, err := uc.recommendationService.GetUserRecommendations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := uc.loadTracks(ctx, recommendations...)
+	if err != nil {
+		return nil, err
+	}
 
+	result := make([]dto.RecommendationDTO, len(recommendations))
+	for i, recommendation := range recommendations {
+		result[i] = dto.RecommendationFromEntity(recommendation, tracks[i])
+	}
+
+	return result, nil
+}
+
+// loadTracks fetches the tracks of all the recommendations in one query and
+// returns them per recommendation, in recommendation order. Tracks that no
+// longer exist are logged and left out.
+func (uc *GetRecommendations) loadTracks(ctx context.Context,
+	recommendations ...*entity.Recommendation) ([][]*entity.Track, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, recommendation := range recommendations {
+		for _, trackID := range recommendation.TrackIDs {
+			if !seen[trackID] {
+				seen[trackID] = true
+				ids = append(ids, trackID)
+			}
+		}
+	}
+
+	found, err := uc.trackRepository.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recommended tracks: %w", err)
+	}
+	byID := make(map[string]*entity.Track, len(found))
+	for _, track := range found {
+		byID[track.ID] = track
+	}
+
+	result := make([][]*entity.Track, len(recommendations))
+	for i, recommendation := range recommendations {
+		for _, trackID := range recommendation.TrackIDs {
+			track, ok := byID[trackID]
+			if !ok {
+				log.Warn().Str("recommendation_id", recommendation.ID).Str("track_id", trackID).
+					Msg("recommended track no longer exists")
+				continue
+			}
+			result[i] = append(result[i], track)
+		}
+	}
+	return result, nil
 }
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"spotify_recommender/internal/domain/valueObject"
+	"testing"
+	"time"
+)
+
+func TestForecastReportWeatherAt(t *testing.T) {
+	start := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	report := ForecastReport{
+		Forecasts: []WeatherForecast{
+			{Time: start, Weather: valueObject.WeatherRainy},
+			{Time: start.Add(time.Hour), Weather: valueObject.WeatherCloudy},
+			{Time: start.Add(2 * time.Hour), Weather: valueObject.WeatherSunny},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		want   valueObject.Weather
+		wantOK bool
+	}{
+		{name: "before the forecast", at: start.Add(-time.Minute)},
+		{name: "at a step", at: start, want: valueObject.WeatherRainy, wantOK: true},
+		{name: "between steps", at: start.Add(90 * time.Minute), want: valueObject.WeatherCloudy, wantOK: true},
+		{name: "after the last step", at: start.Add(5 * time.Hour), want: valueObject.WeatherSunny, wantOK: true},
+		{name: "beyond the forecast", at: start.Add(2*time.Hour + maxForecastStep)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := report.WeatherAt(tt.at)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("WeatherAt() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if _, ok := (ForecastReport{}).WeatherAt(start); ok {
+		t.Error("WeatherAt() on an empty forecast reported a weather")
+	}
+}
@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"spotify_recommender/internal/domain/domainerr"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
+	"spotify_recommender/internal/domain/service"
+	"spotify_recommender/internal/domain/valueObject"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	savedTracksPageSize    = 50
+	playlistsPageSize      = 50
+	playlistTracksPageSize = 100
+)
+
+var ErrLibraryImportNotFound = domainerr.NotFound("library_import_not_found", "no library import has been started")
+
+type SpotifyTrackPage struct {
+	Tracks     []*entity.Track
+	Total      int
+	NextOffset int
+	HasMore    bool
+}
+
+type SpotifyPlaylistSummary struct {
+	ID          string
+	Name        string
+	Description string
+	Public      bool
+}
+
+type SpotifyPlaylistPage struct {
+	Playlists  []SpotifyPlaylistSummary
+	Total      int
+	NextOffset int
+	HasMore    bool
+}
+
+// SpotifyLibrary reads a user's Spotify library on their behalf.
+type SpotifyLibrary interface {
+	SavedTracks(ctx context.Context, userID string, offset, limit int) (*SpotifyTrackPage, error)
+	Playlists(ctx context.Context, userID string, offset, limit int) (*SpotifyPlaylistPage, error)
+	PlaylistTracks(ctx context.Context, userID, spotifyPlaylistID string, offset, limit int) (*SpotifyTrackPage, error)
+}
+
+// ImportSpotifyLibraryUseCase copies a user's saved tracks and playlists into
+// the local catalog. Imports run in the background and checkpoint after every
+// page, so a failed or interrupted import resumes where it stopped.
+type ImportSpotifyLibraryUseCase struct {
+	library      SpotifyLibrary
+	importRepo   repository.LibraryImportRepository
+	trackRepo    repository.TrackRepository
+	playlistRepo repository.PlaylistRepository
+	userRepo     repository.UserRepository
+
+	mutex   sync.Mutex
+	running map[string]*entity.LibraryImport
+}
+
+func NewImportSpotifyLibraryUseCase(
+	library SpotifyLibrary,
+	importRepo repository.LibraryImportRepository,
+	trackRepo repository.TrackRepository,
+	playlistRepo repository.PlaylistRepository,
+	userRepo repository.UserRepository,
+) *ImportSpotifyLibraryUseCase {
+	return &ImportSpotifyLibraryUseCase{
+		library:      library,
+		importRepo:   importRepo,
+		trackRepo:    trackRepo,
+		playlistRepo: playlistRepo,
+		userRepo:     userRepo,
+		running:      make(map[string]*entity.LibraryImport),
+	}
+}
+
+// Start begins an import for the user, resumes their last unfinished one, or
+// returns the import already in progress.
+func (uc *ImportSpotifyLibraryUseCase) Start(ctx context.Context, userID string) (*entity.LibraryImport, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+	if user.SpotifyID == "" {
+		return nil, service.ErrSpotifyNotConnected
+	}
+
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+
+	if job, ok := uc.running[userID]; ok {
+		snapshot := *job
+		return &snapshot, nil
+	}
+
+	job, err := uc.importRepo.GetLatestForUser(ctx, userID)
+	if err != nil || job.IsFinished() {
+		job = entity.NewLibraryImport(userID)
+	} else {
+		job.Resume()
+	}
+
+	if err := uc.importRepo.Save(ctx, job); err != nil {
+		return nil, err
+	}
+
+	snapshot := *job
+	uc.running[userID] = &snapshot
+	go uc.run(context.Background(), job)
+
+	return &snapshot, nil
+}
+
+// Status returns the progress of the user's most recent import.
+func (uc *ImportSpotifyLibraryUseCase) Status(ctx context.Context, userID string) (*entity.LibraryImport, error) {
+	uc.mutex.Lock()
+	if job, ok := uc.running[userID]; ok {
+		snapshot := *job
+		uc.mutex.Unlock()
+		return &snapshot, nil
+	}
+	uc.mutex.Unlock()
+
+	job, err := uc.importRepo.GetLatestForUser(ctx, userID)
+	if err != nil {
+		return nil, ErrLibraryImportNotFound
+	}
+	return job, nil
+}
+
+func (uc *ImportSpotifyLibraryUseCase) run(ctx context.Context, job *entity.LibraryImport) {
+	err := uc.importSavedTracks(ctx, job)
+	if err == nil {
+		err = uc.importPlaylists(ctx, job)
+	}
+
+	if err != nil {
+		log.Error().Err(err).
+			Str("user_id", job.UserID).
+			Str("import_id", job.ID).
+			Msg("spotify library import failed")
+		job.Fail(err)
+	} else {
+		job.Complete()
+	}
+
+	if err := uc.importRepo.Save(ctx, job); err != nil {
+		log.Error().Err(err).
+			Str("import_id", job.ID).
+			Msg("failed to save library import result")
+	}
+
+	uc.mutex.Lock()
+	delete(uc.running, job.UserID)
+	uc.mutex.Unlock()
+}
+
+func (uc *ImportSpotifyLibraryUseCase) importSavedTracks(ctx context.Context, job *entity.LibraryImport) error {
+	for job.Phase == entity.LibraryImportSavedTracks {
+		page, err := uc.library.SavedTracks(ctx, job.UserID, job.SavedTracksOffset, savedTracksPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to fetch saved tracks: %w", err)
+		}
+
+		for _, track := range page.Tracks {
+			if err := uc.trackRepo.Upsert(ctx, track); err != nil {
+				return err
+			}
+			if err := uc.userRepo.LogTrackInteraction(ctx, job.UserID, track.ID, true); err != nil {
+				return err
+			}
+		}
+
+		job.SavedTracksTotal = page.Total
+		job.SavedTracksOffset = page.NextOffset
+		job.TracksImported += len(page.Tracks)
+		if !page.HasMore {
+			job.Phase = entity.LibraryImportPlaylists
+		}
+
+		if err := uc.checkpoint(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uc *ImportSpotifyLibraryUseCase) importPlaylists(ctx context.Context, job *entity.LibraryImport) error {
+	for job.Phase == entity.LibraryImportPlaylists {
+		page, err := uc.library.Playlists(ctx, job.UserID, job.PlaylistsOffset, playlistsPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to fetch playlists: %w", err)
+		}
+
+		job.PlaylistsTotal = page.Total
+
+		// Playlists are checkpointed one at a time since each may need
+		// several requests of its own.
+		for _, summary := range page.Playlists {
+			imported, err := uc.importPlaylist(ctx, job.UserID, summary)
+			if err != nil {
+				return err
+			}
+
+			job.PlaylistsOffset++
+			job.PlaylistsImported++
+			job.TracksImported += imported
+			if err := uc.checkpoint(ctx, job); err != nil {
+				return err
+			}
+		}
+
+		job.PlaylistsOffset = page.NextOffset
+		if !page.HasMore || len(page.Playlists) == 0 {
+			job.Phase = entity.LibraryImportDone
+		}
+	}
+
+	return nil
+}
+
+// importPlaylist stores the playlist's tracks and creates or refreshes the
+// matching local playlist. It returns the number of tracks imported.
+func (uc *ImportSpotifyLibraryUseCase) importPlaylist(
+	ctx context.Context,
+	userID string,
+	summary SpotifyPlaylistSummary,
+) (int, error) {
+	var tracks []*entity.Track
+	for offset, hasMore := 0, true; hasMore; {
+		page, err := uc.library.PlaylistTracks(ctx, userID, summary.ID, offset, playlistTracksPageSize)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch tracks of playlist %s: %w", summary.ID, err)
+		}
+
+		for _, track := range page.Tracks {
+			if err := uc.trackRepo.Upsert(ctx, track); err != nil {
+				return 0, err
+			}
+		}
+
+		tracks = append(tracks, page.Tracks...)
+		offset, hasMore = page.NextOffset, page.HasMore
+	}
+
+	trackIDs := make([]string, 0, len(tracks))
+	seen := make(map[string]bool, len(tracks))
+	for _, track := range tracks {
+		if !seen[track.ID] {
+			seen[track.ID] = true
+			trackIDs = append(trackIDs, track.ID)
+		}
+	}
+
+	playlist, err := uc.playlistRepo.GetBySpotifyPlaylistID(ctx, userID, summary.ID)
+	if err == nil {
+		playlist.Name = summary.Name
+		playlist.Description = summary.Description
+		playlist.IsPublic = summary.Public
+		playlist.Tracks = trackIDs
+		return len(tracks), uc.playlistRepo.Update(ctx, playlist)
+	}
+
+	playlist = entity.NewPlaylist(userID, summary.Name, summary.Description, dominantMood(tracks))
+	playlist.IsPublic = summary.Public
+	playlist.SpotifyPlaylistID = summary.ID
+	playlist.Tracks = trackIDs
+
+	return len(tracks), uc.playlistRepo.Save(ctx, playlist)
+}
+
+func (uc *ImportSpotifyLibraryUseCase) checkpoint(ctx context.Context, job *entity.LibraryImport) error {
+	if err := uc.importRepo.Save(ctx, job); err != nil {
+		return err
+	}
+
+	uc.mutex.Lock()
+	snapshot := *job
+	uc.running[job.UserID] = &snapshot
+	uc.mutex.Unlock()
+
+	return nil
+}
+
+// dominantMood picks the mood matched by the most tracks, falling back to
+// happy like playlists created without a valid mood.
+func dominantMood(tracks []*entity.Track) valueObject.Mood {
+	best, bestCount := valueObject.MoodHappy, 0
+	for _, mood := range valueObject.AllMoods() {
+		count := 0
+		for _, track := range tracks {
+			if track.AudioFeatures.MatchesMood(mood) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = mood, count
+		}
+	}
+	return best
+}
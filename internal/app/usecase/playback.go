@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"spotify_recommender/internal/app/dto"
+	"spotify_recommender/internal/domain/domainerr"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
+	"spotify_recommender/internal/domain/service"
+	"time"
+)
+
+var (
+	ErrPlaybackDeviceUnavailable = domainerr.Conflict("playback_device_unavailable",
+		"no spotify device is available; open spotify on a device or choose another one")
+	ErrNothingToPlay = domainerr.Conflict("nothing_to_play", "none of the tracks are available on spotify")
+)
+
+type SpotifyDevice struct {
+	ID            string
+	Name          string
+	Type          string
+	IsActive      bool
+	IsRestricted  bool
+	VolumePercent int
+}
+
+type SpotifyPlayback struct {
+	Device    SpotifyDevice
+	IsPlaying bool
+	Progress  time.Duration
+	Track     *entity.Track
+}
+
+// SpotifyPlayer controls playback on a user's Spotify devices. An empty
+// deviceID means the user's active device. Track IDs are Spotify IDs.
+type SpotifyPlayer interface {
+	Devices(ctx context.Context, userID string) ([]SpotifyDevice, error)
+	// CurrentPlayback returns nil when the user has no active device.
+	CurrentPlayback(ctx context.Context, userID string) (*SpotifyPlayback, error)
+	Play(ctx context.Context, userID, deviceID string, spotifyTrackIDs []string) error
+	Queue(ctx context.Context, userID, deviceID, spotifyTrackID string) error
+}
+
+type PlaybackUseCase struct {
+	player                SpotifyPlayer
+	recommendationService *service.RecommendationService
+	trackRepo             repository.TrackRepository
+	userRepo              repository.UserRepository
+}
+
+func NewPlaybackUseCase(
+	player SpotifyPlayer,
+	recommendationService *service.RecommendationService,
+	trackRepo repository.TrackRepository,
+	userRepo repository.UserRepository,
+) *PlaybackUseCase {
+	return &PlaybackUseCase{
+		player:                player,
+		recommendationService: recommendationService,
+		trackRepo:             trackRepo,
+		userRepo:              userRepo,
+	}
+}
+
+func (uc *PlaybackUseCase) Devices(ctx context.Context, userID string) ([]dto.SpotifyDeviceDTO, error) {
+	if err := uc.requireSpotify(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	devices, err := uc.player.Devices(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spotify devices: %w", err)
+	}
+
+	result := make([]dto.SpotifyDeviceDTO, len(devices))
+	for i, device := range devices {
+		result[i] = deviceDTO(device)
+	}
+	return result, nil
+}
+
+// CurrentlyPlaying reports the user's playback. The track is matched to our
+// catalog when we know it, so clients can send feedback on it.
+func (uc *PlaybackUseCase) CurrentlyPlaying(ctx context.Context, userID string) (*dto.PlaybackStateDTO, error) {
+	if err := uc.requireSpotify(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	playback, err := uc.player.CurrentPlayback(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spotify playback: %w", err)
+	}
+	if playback == nil {
+		return &dto.PlaybackStateDTO{}, nil
+	}
+
+	device := deviceDTO(playback.Device)
+	result := &dto.PlaybackStateDTO{
+		IsPlaying:  playback.IsPlaying,
+		ProgressMS: playback.Progress.Milliseconds(),
+		Device:     &device,
+	}
+
+	if playback.Track != nil {
+		track := playback.Track
+		if known, err := uc.trackRepo.GetBySpotifyID(ctx, track.SpotifyID); err == nil {
+			track = known
+		}
+		trackDTO := dto.TrackFromEntity(track)
+		result.Track = &trackDTO
+	}
+
+	return result, nil
+}
+
+// PlayRecommendation starts the recommendation's tracks, in order, on the
+// device. Tracks without a Spotify ID are skipped.
+func (uc *PlaybackUseCase) PlayRecommendation(ctx context.Context, userID, recommendationID, deviceID string) error {
+	if err := uc.requireSpotify(ctx, userID); err != nil {
+		return err
+	}
+
+	recommendation, err := uc.recommendationService.GetRecommendation(ctx, userID, recommendationID)
+	if err != nil {
+		return err
+	}
+
+	tracks, err := uc.loadTracks(ctx, recommendation.TrackIDs)
+	if err != nil {
+		return err
+	}
+
+	spotifyTrackIDs := make([]string, 0, len(recommendation.TrackIDs))
+	for _, trackID := range recommendation.TrackIDs {
+		track, ok := tracks[trackID]
+		if !ok || track.SpotifyID == "" {
+			continue
+		}
+		spotifyTrackIDs = append(spotifyTrackIDs, track.SpotifyID)
+	}
+	if len(spotifyTrackIDs) == 0 {
+		return ErrNothingToPlay
+	}
+
+	return playbackError(uc.player.Play(ctx, userID, deviceID, spotifyTrackIDs))
+}
+
+// QueueTracks adds our tracks, in order, to the user's queue on the device.
+func (uc *PlaybackUseCase) QueueTracks(ctx context.Context, userID, deviceID string, trackIDs []string) error {
+	if err := uc.requireSpotify(ctx, userID); err != nil {
+		return err
+	}
+
+	tracks, err := uc.loadTracks(ctx, trackIDs)
+	if err != nil {
+		return err
+	}
+
+	spotifyTrackIDs := make([]string, 0, len(trackIDs))
+	for _, trackID := range trackIDs {
+		track, ok := tracks[trackID]
+		if !ok {
+			return service.ErrTrackNotFound
+		}
+		if track.SpotifyID == "" {
+			return ErrNothingToPlay
+		}
+		spotifyTrackIDs = append(spotifyTrackIDs, track.SpotifyID)
+	}
+
+	for _, spotifyTrackID := range spotifyTrackIDs {
+		if err := uc.player.Queue(ctx, userID, deviceID, spotifyTrackID); err != nil {
+			return playbackError(err)
+		}
+	}
+
+	return nil
+}
+
+// loadTracks returns the tracks with the given IDs, by ID. IDs without a
+// track are left out.
+func (uc *PlaybackUseCase) loadTracks(ctx context.Context, trackIDs []string) (map[string]*entity.Track, error) {
+	found, err := uc.trackRepo.GetByIDs(ctx, trackIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tracks: %w", err)
+	}
+
+	tracks := make(map[string]*entity.Track, len(found))
+	for _, track := range found {
+		tracks[track.ID] = track
+	}
+	return tracks, nil
+}
+
+func (uc *PlaybackUseCase) requireSpotify(ctx context.Context, userID string) error {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return service.ErrUserNotFound
+	}
+	if user.SpotifyID == "" {
+		return service.ErrSpotifyNotConnected
+	}
+	return nil
+}
+
+// playbackError reports Spotify's 404 for player commands, which means the
+// device is gone or none is active, as ErrPlaybackDeviceUnavailable.
+func playbackError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if domainerr.KindOf(err) == domainerr.KindNotFound {
+		return ErrPlaybackDeviceUnavailable.Wrap(err)
+	}
+	return fmt.Errorf("failed to control spotify playback: %w", err)
+}
+
+func deviceDTO(device SpotifyDevice) dto.SpotifyDeviceDTO {
+	return dto.SpotifyDeviceDTO{
+		ID:            device.ID,
+		Name:          device.Name,
+		Type:          device.Type,
+		IsActive:      device.IsActive,
+		IsRestricted:  device.IsRestricted,
+		VolumePercent: device.VolumePercent,
+	}
+}
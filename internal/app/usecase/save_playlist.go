@@ -7,10 +7,10 @@ import (
 )
 
 type SavePlaylistUseCase struct {
-	playlistService service.PlaylistService
+	playlistService *service.PlaylistService
 }
 
-func NewSavePlaylistUseCase(playlistService service.PlaylistService) *SavePlaylistUseCase {
+func NewSavePlaylistUseCase(playlistService *service.PlaylistService) *SavePlaylistUseCase {
 	return &SavePlaylistUseCase{
 		playlistService: playlistService,
 	}
@@ -21,12 +21,17 @@ func (uc *SavePlaylistUseCase) Execute(ctx context.Context,
 	createDTO dto.CreatePlaylistDTO) (*dto.PlaylistDTO, error) {
 	playlist := createDTO.ToEntity(userID)
 
-	savedPlaylist, err := uc.playlistService.CreatePlaylist(ctx, userID, playlist.Name, playlist.Description, playlist.Mood)
+	savedPlaylist, err := uc.playlistService.CreatePlaylist(ctx, userID, playlist.Name, playlist.Description, playlist.Mood, playlist.IsPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := uc.playlistService.GetPlaylistTracks(ctx, userID, savedPlaylist.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	playlistDTO := dto.PlaylistFromEntity(savedPlaylist, nil)
+	playlistDTO := dto.PlaylistFromEntity(savedPlaylist, tracks)
 
 	return &playlistDTO, nil
 }
@@ -52,6 +57,10 @@ func (uc *SavePlaylistFromRecommendationUseCase) Execute(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	playlistDTO := dto.PlaylistFromEntity(playlist, nil)
-	return &playlistDTO, err
+	tracks, err := uc.playlistService.GetPlaylistTracks(ctx, userID, playlist.ID)
+	if err != nil {
+		return nil, err
+	}
+	playlistDTO := dto.PlaylistFromEntity(playlist, tracks)
+	return &playlistDTO, nil
 }
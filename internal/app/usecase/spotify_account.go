@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"spotify_recommender/internal/domain/domainerr"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
+	"spotify_recommender/internal/domain/service"
+	"time"
+)
+
+const oauthStateTTL = 10 * time.Minute
+
+var (
+	ErrInvalidOAuthState = domainerr.Validation("invalid_oauth_state", "authorization state is invalid or expired",
+		domainerr.FieldError{Field: "state", Message: "does not match a pending authorization"})
+	ErrSpotifyAuthorizationDenied = domainerr.Validation("spotify_authorization_denied", "spotify authorization was denied")
+	ErrSpotifyAccountInUse        = domainerr.Conflict("spotify_account_in_use", "spotify account is linked to another user")
+	ErrSpotifyLinkingUnavailable  = domainerr.Unavailable("spotify_linking_unavailable", "spotify account linking is not configured")
+)
+
+type SpotifyAuthClient interface {
+	GetAuthURL(state string, scopes []string) string
+	ExchangeCodeForToken(ctx context.Context, code string) (*entity.SpotifyToken, error)
+	GetTokenOwnerID(ctx context.Context, token *entity.SpotifyToken) (string, error)
+}
+
+type SpotifyAccountUseCase struct {
+	authClient  SpotifyAuthClient
+	accountRepo repository.SpotifyAccountRepository
+	userRepo    repository.UserRepository
+	scopes      []string
+}
+
+// NewSpotifyAccountUseCase builds the use case. accountRepo may be nil, in
+// which case linking is unavailable.
+func NewSpotifyAccountUseCase(
+	authClient SpotifyAuthClient,
+	accountRepo repository.SpotifyAccountRepository,
+	userRepo repository.UserRepository,
+	scopes []string,
+) *SpotifyAccountUseCase {
+	return &SpotifyAccountUseCase{
+		authClient:  authClient,
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		scopes:      scopes,
+	}
+}
+
+// BeginLink records a single-use state for the user and returns the Spotify
+// authorization URL the client should open.
+func (uc *SpotifyAccountUseCase) BeginLink(ctx context.Context, userID string) (string, error) {
+	if uc.accountRepo == nil {
+		return "", ErrSpotifyLinkingUnavailable
+	}
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return "", service.ErrUserNotFound
+	}
+
+	state, err := generateState()
+	if err != nil {
+		return "", err
+	}
+
+	if err := uc.accountRepo.SaveState(ctx, entity.NewOAuthState(state, userID, oauthStateTTL)); err != nil {
+		return "", err
+	}
+
+	return uc.authClient.GetAuthURL(state, uc.scopes), nil
+}
+
+// CompleteLink handles the OAuth callback: it verifies the state, exchanges
+// the code and stores the resulting tokens for the user who started the flow.
+func (uc *SpotifyAccountUseCase) CompleteLink(ctx context.Context, state, code, authError string) error {
+	if uc.accountRepo == nil {
+		return ErrSpotifyLinkingUnavailable
+	}
+	oauthState, err := uc.accountRepo.ConsumeState(ctx, state)
+	if err != nil || oauthState.IsExpired() {
+		return ErrInvalidOAuthState
+	}
+
+	if authError != "" || code == "" {
+		return ErrSpotifyAuthorizationDenied
+	}
+
+	token, err := uc.authClient.ExchangeCodeForToken(ctx, code)
+	if err != nil {
+		return fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	spotifyUserID, err := uc.authClient.GetTokenOwnerID(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to get spotify profile: %w", err)
+	}
+
+	existingUser, err := uc.userRepo.GetBySpotifyID(ctx, spotifyUserID)
+	if err == nil && existingUser != nil && existingUser.ID != oauthState.UserID {
+		return ErrSpotifyAccountInUse
+	}
+
+	token.UserID = oauthState.UserID
+	token.SpotifyUserID = spotifyUserID
+
+	if err := uc.accountRepo.SaveToken(ctx, token); err != nil {
+		return err
+	}
+
+	return uc.userRepo.ConnectSpotifyAccount(ctx, oauthState.UserID, spotifyUserID)
+}
+
+func (uc *SpotifyAccountUseCase) Unlink(ctx context.Context, userID string) error {
+	if uc.accountRepo == nil {
+		return ErrSpotifyLinkingUnavailable
+	}
+	if err := uc.accountRepo.DeleteToken(ctx, userID); err != nil {
+		return err
+	}
+
+	return uc.userRepo.DisconnectSpotifyAccount(ctx, userID)
+}
+
+func generateState() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate oauth state: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
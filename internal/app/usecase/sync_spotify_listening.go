@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	recentlyPlayedPageSize = 50
+	// maxRecentlyPlayedPages bounds a single user's sync; Spotify keeps only
+	// the last 50 plays, so more pages only appear after a long outage.
+	maxRecentlyPlayedPages  = 10
+	topItemsLimit           = 50
+	topItemsRefreshInterval = 24 * time.Hour
+)
+
+type SpotifyPlay struct {
+	Track    *entity.Track
+	PlayedAt time.Time
+}
+
+type SpotifyRecentPlays struct {
+	Plays   []SpotifyPlay
+	HasMore bool
+}
+
+type SpotifyArtist struct {
+	ID     string
+	Name   string
+	Genres []string
+}
+
+// SpotifyListeningHistory reads what a user has been listening to on Spotify.
+type SpotifyListeningHistory interface {
+	RecentlyPlayed(ctx context.Context, userID string, after time.Time, limit int) (*SpotifyRecentPlays, error)
+	TopTracks(ctx context.Context, userID string, timeRange entity.TopTimeRange, limit int) ([]*entity.Track, error)
+	TopArtists(ctx context.Context, userID string, timeRange entity.TopTimeRange, limit int) ([]SpotifyArtist, error)
+}
+
+// SyncSpotifyListeningUseCase periodically copies linked users' recently
+// played tracks and top tracks and artists into their listening history as
+// implicit play events. Recently played is synced incrementally from a
+// per-user cursor; top items change slowly and are refreshed once a day.
+type SyncSpotifyListeningUseCase struct {
+	history     SpotifyListeningHistory
+	historyRepo repository.ListeningHistoryRepository
+	accountRepo repository.SpotifyAccountRepository
+	trackRepo   repository.TrackRepository
+}
+
+func NewSyncSpotifyListeningUseCase(
+	history SpotifyListeningHistory,
+	historyRepo repository.ListeningHistoryRepository,
+	accountRepo repository.SpotifyAccountRepository,
+	trackRepo repository.TrackRepository,
+) *SyncSpotifyListeningUseCase {
+	return &SyncSpotifyListeningUseCase{
+		history:     history,
+		historyRepo: historyRepo,
+		accountRepo: accountRepo,
+		trackRepo:   trackRepo,
+	}
+}
+
+// Run syncs every linked user straight away and then once per interval,
+// until ctx is cancelled.
+func (uc *SyncSpotifyListeningUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		uc.SyncAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncAll syncs every linked user. A failure for one user is logged and does
+// not stop the others.
+func (uc *SyncSpotifyListeningUseCase) SyncAll(ctx context.Context) {
+	userIDs, err := uc.accountRepo.ListLinkedUserIDs(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list users for spotify listening sync")
+		return
+	}
+
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := uc.SyncUser(ctx, userID); err != nil {
+			log.Warn().Err(err).
+				Str("user_id", userID).
+				Msg("spotify listening sync failed")
+		}
+	}
+}
+
+func (uc *SyncSpotifyListeningUseCase) SyncUser(ctx context.Context, userID string) error {
+	state, err := uc.historyRepo.GetSyncState(ctx, userID)
+	if err != nil {
+		state = entity.NewListeningSync(userID)
+	}
+
+	if err := uc.syncRecentlyPlayed(ctx, state); err != nil {
+		return err
+	}
+
+	if state.TopItemsDue(time.Now(), topItemsRefreshInterval) {
+		if err := uc.syncTopItems(ctx, state); err != nil {
+			// Keep the recently played progress even if top items failed.
+			if saveErr := uc.historyRepo.SaveSyncState(ctx, state); saveErr != nil {
+				return saveErr
+			}
+			return err
+		}
+	}
+
+	return uc.historyRepo.SaveSyncState(ctx, state)
+}
+
+func (uc *SyncSpotifyListeningUseCase) syncRecentlyPlayed(ctx context.Context, state *entity.ListeningSync) error {
+	for page := 0; page < maxRecentlyPlayedPages; page++ {
+		recent, err := uc.history.RecentlyPlayed(ctx, state.UserID, state.RecentlyPlayedCursor, recentlyPlayedPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to fetch recently played tracks: %w", err)
+		}
+
+		cursor := state.RecentlyPlayedCursor
+		events := make([]*entity.PlayEvent, 0, len(recent.Plays))
+		for _, play := range recent.Plays {
+			if err := uc.trackRepo.Upsert(ctx, play.Track); err != nil {
+				return err
+			}
+			events = append(events, entity.NewRecentPlayEvent(state.UserID, play.Track.ID, play.PlayedAt))
+			if play.PlayedAt.After(cursor) {
+				cursor = play.PlayedAt
+			}
+		}
+
+		if err := uc.historyRepo.SaveRecentPlays(ctx, events); err != nil {
+			return err
+		}
+		state.RecentlyPlayedCursor = cursor
+
+		if !recent.HasMore || len(recent.Plays) == 0 {
+			break
+		}
+	}
+
+	state.RecentlyPlayedSyncedAt = time.Now()
+	return nil
+}
+
+func (uc *SyncSpotifyListeningUseCase) syncTopItems(ctx context.Context, state *entity.ListeningSync) error {
+	now := time.Now()
+
+	for _, timeRange := range entity.AllTopTimeRanges() {
+		tracks, err := uc.history.TopTracks(ctx, state.UserID, timeRange, topItemsLimit)
+		if err != nil {
+			return fmt.Errorf("failed to fetch top tracks: %w", err)
+		}
+
+		trackEvents := make([]*entity.PlayEvent, 0, len(tracks))
+		for i, track := range tracks {
+			if err := uc.trackRepo.Upsert(ctx, track); err != nil {
+				return err
+			}
+			trackEvents = append(trackEvents, entity.NewTopTrackEvent(state.UserID, track.ID, timeRange, i+1, now))
+		}
+
+		err = uc.historyRepo.ReplaceTopItems(ctx, state.UserID, entity.PlayEventTopTrack, timeRange, trackEvents)
+		if err != nil {
+			return err
+		}
+
+		artists, err := uc.history.TopArtists(ctx, state.UserID, timeRange, topItemsLimit)
+		if err != nil {
+			return fmt.Errorf("failed to fetch top artists: %w", err)
+		}
+
+		artistEvents := make([]*entity.PlayEvent, 0, len(artists))
+		for i, artist := range artists {
+			artistEvents = append(artistEvents,
+				entity.NewTopArtistEvent(state.UserID, artist.ID, artist.Name, artist.Genres, timeRange, i+1, now))
+		}
+
+		err = uc.historyRepo.ReplaceTopItems(ctx, state.UserID, entity.PlayEventTopArtist, timeRange, artistEvents)
+		if err != nil {
+			return err
+		}
+	}
+
+	state.TopItemsSyncedAt = now
+	return nil
+}
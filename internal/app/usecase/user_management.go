@@ -2,11 +2,12 @@ package usecase
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"golang.org/x/crypto/bcrypt"
 	"spotify_recommender/internal/app/dto"
 	"spotify_recommender/internal/domain/entity"
 	"spotify_recommender/internal/domain/repository"
+	"spotify_recommender/internal/domain/service"
 	"time"
 )
 
@@ -25,7 +26,7 @@ func NewUserManagementUseCase(
 func (uc *UserManagementUseCase) Register(ctx context.Context, createDTO dto.CreateUserDTO) (*dto.UserDTO, error) {
 	existingUser, err := uc.userRepository.GetByEmail(ctx, createDTO.Email)
 	if err == nil && existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, ErrEmailTaken
 	}
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(createDTO.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -33,6 +34,7 @@ func (uc *UserManagementUseCase) Register(ctx context.Context, createDTO dto.Cre
 	}
 
 	user := entity.NewUser(createDTO.Email, string(hashedPassword), createDTO.Name)
+	user.Timezone = createDTO.Timezone
 
 	// Устанавливаем предпочтения, если они предоставлены
 	if createDTO.Preferences.FavoriteGenres != nil {
@@ -50,10 +52,11 @@ func (uc *UserManagementUseCase) Register(ctx context.Context, createDTO dto.Cre
 	if createDTO.Preferences.PreferredMoods != nil {
 		user.Preferences.PreferredMoods = createDTO.Preferences.PreferredMoods
 	}
+	user.Preferences.TimeOfDayMode = createDTO.Preferences.TimeOfDayMode
 
 	err = uc.userRepository.Save(ctx, user)
 	if err != nil {
-		return nil, errors.New("Error with saving user")
+		return nil, fmt.Errorf("failed to save user: %w", err)
 	}
 
 	userDTO := dto.UserFromEntity(user)
@@ -64,12 +67,12 @@ func (uc *UserManagementUseCase) Register(ctx context.Context, createDTO dto.Cre
 func (uc *UserManagementUseCase) Login(ctx context.Context, email string,
 	password string) (*dto.UserDTO, error) {
 	existingUser, err := uc.userRepository.GetByEmail(ctx, email)
-	if err == nil && existingUser != nil {
-		return nil, errors.New("no such an email")
+	if err != nil || existingUser == nil {
+		return nil, ErrInvalidCredentials
 	}
 	err = bcrypt.CompareHashAndPassword([]byte(existingUser.PasswordHash), []byte(password))
 	if err != nil {
-		return nil, errors.New("wrong password")
+		return nil, ErrInvalidCredentials
 	}
 
 	existingUser.LastLoginAt = time.Now()
@@ -82,13 +85,38 @@ func (uc *UserManagementUseCase) Login(ctx context.Context, email string,
 	return &userDTO, nil
 }
 
+func (uc *UserManagementUseCase) ChangePassword(
+	ctx context.Context,
+	userID string,
+	changeDTO dto.ChangePasswordDTO,
+) error {
+	user, err := uc.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return service.ErrUserNotFound
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(changeDTO.CurrentPassword))
+	if err != nil {
+		return ErrWrongPassword
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(changeDTO.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.PasswordHash = string(hashedPassword)
+
+	return uc.userRepository.Update(ctx, user)
+}
+
 func (uc *UserManagementUseCase) GetUserProfile(
 	ctx context.Context,
 	userID string,
 ) (*dto.UserDTO, error) {
 	user, err := uc.userRepository.GetByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, service.ErrUserNotFound
 	}
 
 	userDTO := dto.UserFromEntity(user)
@@ -103,7 +131,7 @@ func (uc *UserManagementUseCase) UpdatePreferences(
 ) (*dto.UserDTO, error) {
 	user, err := uc.userRepository.GetByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, service.ErrUserNotFound
 	}
 
 	preferences := entity.Preferences{
@@ -112,6 +140,7 @@ func (uc *UserManagementUseCase) UpdatePreferences(
 		MinTempo:       preferencesDTO.MinTempo,
 		MaxTempo:       preferencesDTO.MaxTempo,
 		PreferredMoods: preferencesDTO.PreferredMoods,
+		TimeOfDayMode:  preferencesDTO.TimeOfDayMode,
 	}
 
 	err = uc.userRepository.UpdatePreferences(ctx, userID, preferences)
@@ -125,3 +154,28 @@ func (uc *UserManagementUseCase) UpdatePreferences(
 
 	return &userDTO, nil
 }
+
+func (uc *UserManagementUseCase) UpdateTimezone(
+	ctx context.Context,
+	userID string,
+	timezoneDTO dto.UpdateTimezoneDTO,
+) (*dto.UserDTO, error) {
+	user, err := uc.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+
+	user.Timezone = timezoneDTO.Timezone
+	if _, ok := user.Location(); !ok {
+		return nil, ErrInvalidTimezone
+	}
+
+	err = uc.userRepository.Update(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	userDTO := dto.UserFromEntity(user)
+
+	return &userDTO, nil
+}
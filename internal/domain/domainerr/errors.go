@@ -0,0 +1,96 @@
+package domainerr
+
+import "errors"
+
+type Kind string
+
+const (
+	KindInternal     Kind = "internal"
+	KindNotFound     Kind = "not_found"
+	KindValidation   Kind = "validation"
+	KindConflict     Kind = "conflict"
+	KindUnauthorized Kind = "unauthorized"
+	KindUnavailable  Kind = "upstream_unavailable"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error with a stable machine-readable code. Two errors
+// with the same code match under errors.Is, so sentinels keep working after
+// they are rebuilt with field details or a cause.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of the error carrying cause.
+func (e *Error) Wrap(cause error) *Error {
+	clone := *e
+	clone.Err = cause
+	return &clone
+}
+
+// WithFields returns a copy of the error carrying field-level details.
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	clone := *e
+	clone.Fields = append(append([]FieldError{}, e.Fields...), fields...)
+	return &clone
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func Validation(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message, Fields: fields}
+}
+
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+func Unavailable(code, message string) *Error {
+	return &Error{Kind: KindUnavailable, Code: code, Message: message}
+}
+
+// From returns the first domain error in err's chain, if any.
+func From(err error) (*Error, bool) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
+
+func KindOf(err error) Kind {
+	if domainErr, ok := From(err); ok {
+		return domainErr.Kind
+	}
+	return KindInternal
+}
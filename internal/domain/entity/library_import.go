@@ -0,0 +1,75 @@
+package entity
+
+import "time"
+
+type LibraryImportStatus string
+
+const (
+	LibraryImportRunning   LibraryImportStatus = "running"
+	LibraryImportCompleted LibraryImportStatus = "completed"
+	LibraryImportFailed    LibraryImportStatus = "failed"
+)
+
+type LibraryImportPhase string
+
+const (
+	LibraryImportSavedTracks LibraryImportPhase = "saved_tracks"
+	LibraryImportPlaylists   LibraryImportPhase = "playlists"
+	LibraryImportDone        LibraryImportPhase = "done"
+)
+
+// LibraryImport tracks an import of a user's Spotify library. The offsets
+// are the resume point: everything before them has been imported.
+type LibraryImport struct {
+	ID                string              `json:"id"`
+	UserID            string              `json:"user_id"`
+	Status            LibraryImportStatus `json:"status"`
+	Phase             LibraryImportPhase  `json:"phase"`
+	SavedTracksOffset int                 `json:"saved_tracks_offset"`
+	SavedTracksTotal  int                 `json:"saved_tracks_total"`
+	PlaylistsOffset   int                 `json:"playlists_offset"`
+	PlaylistsTotal    int                 `json:"playlists_total"`
+	TracksImported    int                 `json:"tracks_imported"`
+	PlaylistsImported int                 `json:"playlists_imported"`
+	Error             string              `json:"error,omitempty"`
+	StartedAt         time.Time           `json:"started_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+	CompletedAt       time.Time           `json:"completed_at,omitempty"`
+}
+
+func NewLibraryImport(userID string) *LibraryImport {
+	now := time.Now()
+	return &LibraryImport{
+		UserID:    userID,
+		Status:    LibraryImportRunning,
+		Phase:     LibraryImportSavedTracks,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (i *LibraryImport) IsFinished() bool {
+	return i.Status == LibraryImportCompleted
+}
+
+// Resume marks a failed or interrupted import as running again. Its offsets
+// are kept so work continues where it stopped.
+func (i *LibraryImport) Resume() {
+	i.Status = LibraryImportRunning
+	i.Error = ""
+	i.UpdatedAt = time.Now()
+}
+
+func (i *LibraryImport) Complete() {
+	now := time.Now()
+	i.Status = LibraryImportCompleted
+	i.Phase = LibraryImportDone
+	i.UpdatedAt = now
+	i.CompletedAt = now
+}
+
+func (i *LibraryImport) Fail(err error) {
+	i.Status = LibraryImportFailed
+	i.Error = err.Error()
+	i.UpdatedAt = time.Now()
+}
@@ -0,0 +1,108 @@
+package entity
+
+import "time"
+
+type PlayEventSource string
+
+const (
+	PlayEventRecentlyPlayed PlayEventSource = "recently_played"
+	PlayEventTopTrack       PlayEventSource = "top_track"
+	PlayEventTopArtist      PlayEventSource = "top_artist"
+)
+
+// TopTimeRange is the period Spotify computes a user's top items over.
+type TopTimeRange string
+
+const (
+	TopTimeRangeShort  TopTimeRange = "short_term"
+	TopTimeRangeMedium TopTimeRange = "medium_term"
+	TopTimeRangeLong   TopTimeRange = "long_term"
+)
+
+func AllTopTimeRanges() []TopTimeRange {
+	return []TopTimeRange{
+		TopTimeRangeShort,
+		TopTimeRangeMedium,
+		TopTimeRangeLong,
+	}
+}
+
+// PlayEvent is an implicit listening signal synced from Spotify, as opposed
+// to the explicit likes and dislikes in user_track_interactions. Recently
+// played events reference a track; top artist events reference an artist
+// and carry no track.
+type PlayEvent struct {
+	ID              string          `json:"id"`
+	UserID          string          `json:"user_id"`
+	Source          PlayEventSource `json:"source"`
+	TrackID         string          `json:"track_id,omitempty"`
+	SpotifyArtistID string          `json:"spotify_artist_id,omitempty"`
+	ArtistName      string          `json:"artist_name,omitempty"`
+	Genres          []string        `json:"genres,omitempty"`
+	TimeRange       TopTimeRange    `json:"time_range,omitempty"`
+	Rank            int             `json:"rank,omitempty"`
+	PlayedAt        time.Time       `json:"played_at"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+func NewRecentPlayEvent(userID, trackID string, playedAt time.Time) *PlayEvent {
+	return &PlayEvent{
+		UserID:    userID,
+		Source:    PlayEventRecentlyPlayed,
+		TrackID:   trackID,
+		PlayedAt:  playedAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NewTopTrackEvent records the track at rank (1-based) of the user's top
+// tracks for the time range, as of syncedAt.
+func NewTopTrackEvent(userID, trackID string, timeRange TopTimeRange, rank int, syncedAt time.Time) *PlayEvent {
+	return &PlayEvent{
+		UserID:    userID,
+		Source:    PlayEventTopTrack,
+		TrackID:   trackID,
+		TimeRange: timeRange,
+		Rank:      rank,
+		PlayedAt:  syncedAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+func NewTopArtistEvent(
+	userID, spotifyArtistID, artistName string,
+	genres []string,
+	timeRange TopTimeRange,
+	rank int,
+	syncedAt time.Time,
+) *PlayEvent {
+	return &PlayEvent{
+		UserID:          userID,
+		Source:          PlayEventTopArtist,
+		SpotifyArtistID: spotifyArtistID,
+		ArtistName:      artistName,
+		Genres:          genres,
+		TimeRange:       timeRange,
+		Rank:            rank,
+		PlayedAt:        syncedAt,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// ListeningSync is the per-user state of the Spotify listening history sync.
+// RecentlyPlayedCursor is the played_at of the newest play already stored;
+// only plays after it are fetched on the next run.
+type ListeningSync struct {
+	UserID                 string    `json:"user_id"`
+	RecentlyPlayedCursor   time.Time `json:"recently_played_cursor"`
+	RecentlyPlayedSyncedAt time.Time `json:"recently_played_synced_at"`
+	TopItemsSyncedAt       time.Time `json:"top_items_synced_at"`
+}
+
+func NewListeningSync(userID string) *ListeningSync {
+	return &ListeningSync{UserID: userID}
+}
+
+func (s *ListeningSync) TopItemsDue(now time.Time, interval time.Duration) bool {
+	return s.TopItemsSyncedAt.IsZero() || now.Sub(s.TopItemsSyncedAt) >= interval
+}
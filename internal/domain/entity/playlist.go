@@ -15,8 +15,11 @@ type Playlist struct {
 	TimeOfDay   valueObject.TimeOfDay `json:"time_of_day,omitempty"`
 	Tracks      []string              `json:"track_ids"`
 	IsPublic    bool                  `json:"is_public"`
-	CreatedAt   time.Time             `json:"created_at"`
-	UpdatedAt   time.Time             `json:"updated_at"`
+	// SpotifyPlaylistID is set once the playlist has been exported to the
+	// owner's Spotify account.
+	SpotifyPlaylistID string    `json:"spotify_playlist_id,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 func NewPlaylist(userID, name, description string, mood valueObject.Mood) *Playlist {
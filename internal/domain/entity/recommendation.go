@@ -11,6 +11,8 @@ type Recommendation struct {
 	Mood      valueObject.Mood      `json:"mood"`
 	Weather   valueObject.Weather   `json:"weather"`
 	TimeOfDay valueObject.TimeOfDay `json:"time_of_day"`
+	DayPart   valueObject.DayPart   `json:"day_part,omitempty"`
+	DayType   valueObject.DayType   `json:"day_type,omitempty"`
 	TrackIDs  []string              `json:"track_ids"`
 	CreatedAt time.Time             `json:"created_at"`
 	ExpiresAt time.Time             `json:"expires_at"`
@@ -20,7 +22,7 @@ func NewRecommendation(
 	userID string,
 	mood valueObject.Mood,
 	weather valueObject.Weather,
-	timeOfDay valueObject.TimeOfDay,
+	temporal valueObject.TemporalContext,
 	trackIDs []string,
 ) *Recommendation {
 	now := time.Now()
@@ -28,7 +30,9 @@ func NewRecommendation(
 		UserID:    userID,
 		Mood:      mood,
 		Weather:   weather,
-		TimeOfDay: timeOfDay,
+		TimeOfDay: temporal.TimeOfDay,
+		DayPart:   temporal.DayPart,
+		DayType:   temporal.DayType,
 		TrackIDs:  trackIDs,
 		CreatedAt: now,
 		ExpiresAt: now.Add(24 * time.Hour),
@@ -38,3 +42,11 @@ func NewRecommendation(
 func (r *Recommendation) IsExpired() bool {
 	return time.Now().After(r.ExpiresAt)
 }
+
+func (r *Recommendation) TemporalContext() valueObject.TemporalContext {
+	return valueObject.TemporalContext{
+		TimeOfDay: r.TimeOfDay,
+		DayPart:   r.DayPart,
+		DayType:   r.DayType,
+	}
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+type Session struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at,omitempty"`
+}
+
+type RefreshToken struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	UserID    string    `json:"user_id"`
+	TokenHash string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UsedAt    time.Time `json:"used_at,omitempty"`
+}
+
+func NewSession(userID string, duration time.Duration) *Session {
+	now := time.Now()
+	return &Session{
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(duration),
+	}
+}
+
+func (s *Session) IsActive() bool {
+	return s.RevokedAt.IsZero() && time.Now().Before(s.ExpiresAt)
+}
+
+func NewRefreshToken(session *Session, tokenHash string) *RefreshToken {
+	return &RefreshToken{
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		TokenHash: tokenHash,
+		CreatedAt: time.Now(),
+		ExpiresAt: session.ExpiresAt,
+	}
+}
+
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
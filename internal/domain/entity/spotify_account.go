@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// tokenExpiryDelta makes tokens count as expired slightly early so a request
+// started with a valid token does not reach Spotify after it has lapsed.
+const tokenExpiryDelta = time.Minute
+
+type SpotifyToken struct {
+	UserID        string    `json:"user_id"`
+	SpotifyUserID string    `json:"spotify_user_id"`
+	AccessToken   string    `json:"-"`
+	RefreshToken  string    `json:"-"`
+	TokenType     string    `json:"token_type"`
+	Scope         string    `json:"scope"`
+	Expiry        time.Time `json:"expiry"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (t *SpotifyToken) Valid() bool {
+	return t != nil && t.AccessToken != "" && time.Now().Add(tokenExpiryDelta).Before(t.Expiry)
+}
+
+type OAuthState struct {
+	State     string    `json:"state"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func NewOAuthState(state, userID string, ttl time.Duration) *OAuthState {
+	now := time.Now()
+	return &OAuthState{
+		State:     state,
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+func (s *OAuthState) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
@@ -5,19 +5,45 @@ import (
 	"time"
 )
 
+// ReleaseDatePrecision tells how much of a track's release date is known.
+// Spotify only knows the year, or the year and month, of many older releases.
+type ReleaseDatePrecision string
+
+const (
+	ReleaseDatePrecisionYear  ReleaseDatePrecision = "year"
+	ReleaseDatePrecisionMonth ReleaseDatePrecision = "month"
+	ReleaseDatePrecisionDay   ReleaseDatePrecision = "day"
+)
+
+// TrackArtist is one of the artists credited on a track.
+type TrackArtist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Track is a song in our catalog. Artist is the primary artist's name, kept
+// for display and search; Artists lists everyone credited. Genres are those
+// of the credited artists, as Spotify does not tag tracks themselves.
 type Track struct {
-	ID            string                    `json:"id"`
-	SpotifyID     string                    `json:"spotify_id"`
-	Name          string                    `json:"name"`
-	Artist        string                    `json:"artist"`
-	Album         string                    `json:"album"`
-	ReleaseDate   time.Time                 `json:"release_date"`
-	Popularity    int                       `json:"popularity"`
-	AudioFeatures valueObject.AudioFeatures `json:"audio_features"`
-	PreviewURL    string                    `json:"preview_url"`
-	ImageURL      string                    `json:"image_url"`
-	CreatedAt     time.Time                 `json:"created_at"`
-	UpdatedAt     time.Time                 `json:"updated_at"`
+	ID                   string                    `json:"id"`
+	SpotifyID            string                    `json:"spotify_id"`
+	Name                 string                    `json:"name"`
+	Artist               string                    `json:"artist"`
+	Artists              []TrackArtist             `json:"artists"`
+	Genres               []string                  `json:"genres"`
+	Album                string                    `json:"album"`
+	ReleaseDate          time.Time                 `json:"release_date"`
+	ReleaseDatePrecision ReleaseDatePrecision      `json:"release_date_precision"`
+	ISRC                 string                    `json:"isrc"`
+	Explicit             bool                      `json:"explicit"`
+	DurationMS           int                       `json:"duration_ms"`
+	Popularity           int                       `json:"popularity"`
+	AudioFeatures        valueObject.AudioFeatures `json:"audio_features"`
+	PreviewURL           string                    `json:"preview_url"`
+	ExternalURL          string                    `json:"external_url"`
+	ImageURL             string                    `json:"image_url"`
+	CreatedAt            time.Time                 `json:"created_at"`
+	UpdatedAt            time.Time                 `json:"updated_at"`
 }
 
 func NewTrack(
@@ -28,16 +54,45 @@ func NewTrack(
 	previewURL, imageURL string,
 ) *Track {
 	return &Track{
-		SpotifyID:     spotifyID,
-		Name:          name,
-		Artist:        artist,
-		Album:         album,
-		ReleaseDate:   releaseDate,
-		Popularity:    popularity,
-		AudioFeatures: audioFeatures,
-		PreviewURL:    previewURL,
-		ImageURL:      imageURL,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		SpotifyID:            spotifyID,
+		Name:                 name,
+		Artist:               artist,
+		Album:                album,
+		ReleaseDate:          releaseDate,
+		ReleaseDatePrecision: ReleaseDatePrecisionDay,
+		Popularity:           popularity,
+		AudioFeatures:        audioFeatures,
+		PreviewURL:           previewURL,
+		ImageURL:             imageURL,
+		CreatedAt:            time.Now(),
+		UpdatedAt:            time.Now(),
+	}
+}
+
+// SetArtists credits the track to the artists, the first being the primary
+// one.
+func (t *Track) SetArtists(artists []TrackArtist) {
+	t.Artists = artists
+	if len(artists) > 0 {
+		t.Artist = artists[0].Name
+	}
+}
+
+// ArtistIDs returns the Spotify IDs of the credited artists.
+func (t *Track) ArtistIDs() []string {
+	ids := make([]string, 0, len(t.Artists))
+	for _, artist := range t.Artists {
+		if artist.ID != "" {
+			ids = append(ids, artist.ID)
+		}
+	}
+	return ids
+}
+
+// ReleaseYear returns the year the track was released, or 0 when unknown.
+func (t *Track) ReleaseYear() int {
+	if t.ReleaseDate.IsZero() {
+		return 0
 	}
+	return t.ReleaseDate.Year()
 }
@@ -8,6 +8,7 @@ type User struct {
 	PasswordHash string      `json:"password_hash"`
 	Name         string      `json:"name"`
 	SpotifyID    string      `json:"spotify_id,omitempty"`
+	Timezone     string      `json:"timezone,omitempty"`
 	Preferences  Preferences `json:"preferences"`
 	LastLoginAt  time.Time   `json:"last_login_at"`
 	CreatedAt    time.Time   `json:"created_at"`
@@ -19,6 +20,7 @@ type Preferences struct {
 	MinTempo       float64  `json:"min_tempo"`
 	MaxTempo       float64  `json:"max_tempo"`
 	PreferredMoods []string `json:"preferred_moods"`
+	TimeOfDayMode  string   `json:"time_of_day_mode,omitempty"`
 }
 
 func NewUser(email, passwordHash, name string) *User {
@@ -38,3 +40,16 @@ func NewUser(email, passwordHash, name string) *User {
 		UpdatedAt:   time.Now(),
 	}
 }
+
+// Location returns the user's time zone, an IANA name such as
+// "Asia/Tokyo". It reports false when the user has none or it is unknown.
+func (u *User) Location() (*time.Location, bool) {
+	if u.Timezone == "" {
+		return nil, false
+	}
+	location, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return nil, false
+	}
+	return location, true
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"spotify_recommender/internal/domain/entity"
+)
+
+type LibraryImportRepository interface {
+	Save(ctx context.Context, libraryImport *entity.LibraryImport) error
+	GetLatestForUser(ctx context.Context, userID string) (*entity.LibraryImport, error)
+}
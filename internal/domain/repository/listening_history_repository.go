@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"spotify_recommender/internal/domain/entity"
+	"time"
+)
+
+type ListeningHistoryRepository interface {
+	// SaveRecentPlays stores recently played events, ignoring plays that are
+	// already stored so overlapping syncs are harmless.
+	SaveRecentPlays(ctx context.Context, events []*entity.PlayEvent) error
+	// ReplaceTopItems swaps the user's stored top items of the source and time
+	// range for events.
+	ReplaceTopItems(ctx context.Context, userID string, source entity.PlayEventSource,
+		timeRange entity.TopTimeRange, events []*entity.PlayEvent) error
+
+	GetRecentPlays(ctx context.Context, userID string, since time.Time, limit int) ([]*entity.PlayEvent, error)
+	GetTopItems(ctx context.Context, userID string, source entity.PlayEventSource,
+		timeRange entity.TopTimeRange) ([]*entity.PlayEvent, error)
+
+	GetSyncState(ctx context.Context, userID string) (*entity.ListeningSync, error)
+	SaveSyncState(ctx context.Context, state *entity.ListeningSync) error
+}
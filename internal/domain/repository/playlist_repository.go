@@ -8,18 +8,21 @@ import (
 
 type PlaylistRepository interface {
 	GetByID(ctx context.Context, id string) (*entity.Playlist, error)
+	GetBySpotifyPlaylistID(ctx context.Context, userID, spotifyPlaylistID string) (*entity.Playlist, error)
 	Save(ctx context.Context, playlist *entity.Playlist) error
 	Update(ctx context.Context, playlist *entity.Playlist) error
 	Delete(ctx context.Context, id string) error
+	SetSpotifyPlaylistID(ctx context.Context, playlistID, spotifyPlaylistID string) error
 
 	GetUserPlaylists(ctx context.Context, userID string) ([]*entity.Playlist, error)
 
-	FindByName(ctx context.Context, name string) ([]*entity.Playlist, error)
-	FindByMood(ctx context.Context, mood valueObject.Mood) ([]*entity.Playlist, error)
+	FindByName(ctx context.Context, name string, limit, offset int) ([]*entity.Playlist, error)
+	FindByMood(ctx context.Context, mood valueObject.Mood, limit, offset int) ([]*entity.Playlist, error)
 
 	AddTrackToPlaylist(ctx context.Context, playlistID, trackID string) error
 	RemoveTrackFromPlaylist(ctx context.Context, playlistID, trackID string) error
 	GetPlaylistTracks(ctx context.Context, playlistID string) ([]*entity.Track, error)
+	GetPlaylistsTracks(ctx context.Context, playlistIDs []string) (map[string][]*entity.Track, error)
 
 	GetPublicPlaylists(ctx context.Context, limit, offset int) ([]*entity.Playlist, error)
 }
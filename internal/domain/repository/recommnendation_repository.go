@@ -18,7 +18,7 @@ type RecommendationRepository interface {
 		userID string,
 		mood valueObject.Mood,
 		weather valueObject.Weather,
-		timeOfDay valueObject.TimeOfDay,
+		temporal valueObject.TemporalContext,
 	) (*entity.Recommendation, error)
 
 	DeleteExpired(ctx context.Context) error
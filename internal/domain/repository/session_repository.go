@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"spotify_recommender/internal/domain/entity"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *entity.Session) error
+	GetSession(ctx context.Context, id string) (*entity.Session, error)
+	RevokeSession(ctx context.Context, id string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+
+	SaveRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	// MarkRefreshTokenUsed reports false if the token had already been used.
+	MarkRefreshTokenUsed(ctx context.Context, id string) (bool, error)
+
+	DeleteExpired(ctx context.Context) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"spotify_recommender/internal/domain/entity"
+)
+
+type SpotifyAccountRepository interface {
+	GetToken(ctx context.Context, userID string) (*entity.SpotifyToken, error)
+	SaveToken(ctx context.Context, token *entity.SpotifyToken) error
+	DeleteToken(ctx context.Context, userID string) error
+	// ListLinkedUserIDs returns the IDs of all users with stored Spotify tokens.
+	ListLinkedUserIDs(ctx context.Context) ([]string, error)
+
+	SaveState(ctx context.Context, state *entity.OAuthState) error
+	// ConsumeState deletes the state and returns it, so each state can be used once.
+	ConsumeState(ctx context.Context, state string) (*entity.OAuthState, error)
+}
@@ -5,7 +5,7 @@ import (
 	"spotify_recommender/internal/domain/entity"
 )
 
-type User interface {
+type UserRepository interface {
 	GetByID(ctx context.Context, id string) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetBySpotifyID(ctx context.Context, spotifyID string) (*entity.User, error)
@@ -18,4 +18,8 @@ type User interface {
 	UpdatePreferences(ctx context.Context, userID string, preferences entity.Preferences) error
 
 	LogTrackInteraction(ctx context.Context, userID, trackID string, liked bool) error
+	GetUserLikedTracks(ctx context.Context, userID string, limit, offset int) ([]*entity.Track, int, error)
+
+	ConnectSpotifyAccount(ctx context.Context, userID, spotifyID string) error
+	DisconnectSpotifyAccount(ctx context.Context, userID string) error
 }
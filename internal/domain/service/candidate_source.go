@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
+	"spotify_recommender/internal/domain/valueObject"
+)
+
+type CandidateRequest struct {
+	UserID   string
+	Mood     valueObject.Mood
+	Weather  valueObject.Weather
+	Temporal valueObject.TemporalContext
+	// Seeds steer sources that generate candidates, such as Spotify. Sources
+	// that only filter a catalog ignore them.
+	Seeds valueObject.RecommendationSeeds
+	Limit int
+}
+
+// CandidateSource supplies tracks a recommendation can be built from.
+type CandidateSource interface {
+	Candidates(ctx context.Context, request CandidateRequest) ([]*entity.Track, error)
+}
+
+// CatalogCandidateSource draws candidates from our own track catalog.
+type CatalogCandidateSource struct {
+	trackRepo repository.TrackRepository
+}
+
+func NewCatalogCandidateSource(trackRepo repository.TrackRepository) *CatalogCandidateSource {
+	return &CatalogCandidateSource{
+		trackRepo: trackRepo,
+	}
+}
+
+func (s *CatalogCandidateSource) Candidates(ctx context.Context, request CandidateRequest) ([]*entity.Track, error) {
+	return s.trackRepo.FindByMoodWeatherTime(ctx, request.Mood, request.Weather, request.Temporal, request.Limit)
+}
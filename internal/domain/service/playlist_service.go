@@ -2,47 +2,76 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"spotify_recommender/internal/domain/domainerr"
 	"spotify_recommender/internal/domain/entity"
 	"spotify_recommender/internal/domain/repository"
 	"spotify_recommender/internal/domain/valueObject"
 )
 
 var (
-	ErrPlaylistNotFound = errors.New("playlist not found")
-	ErrTrackNotFound    = errors.New("track not found")
-	ErrUserNotFound     = errors.New("user not found")
+	ErrPlaylistNotFound = domainerr.NotFound("playlist_not_found", "playlist not found")
+	ErrTrackNotFound    = domainerr.NotFound("track_not_found", "track not found")
+	ErrUserNotFound     = domainerr.NotFound("user_not_found", "user not found")
+	ErrInvalidMood      = domainerr.Validation("invalid_mood", "invalid mood value",
+		domainerr.FieldError{Field: "mood", Message: "must be one of the supported moods"})
+	ErrSpotifyExportUnavailable = domainerr.Unavailable("spotify_export_unavailable", "spotify export is not configured")
+	ErrSpotifyNotConnected      = domainerr.Conflict("spotify_not_connected", "spotify account is not connected")
 )
 
+type SpotifyPlaylistDetails struct {
+	Name        string
+	Description string
+	Public      bool
+}
+
+// SpotifyPlaylistPublisher writes playlists to a user's Spotify account on
+// their behalf. userID is our user ID; track IDs are Spotify IDs.
+type SpotifyPlaylistPublisher interface {
+	CreatePlaylist(ctx context.Context, userID, spotifyUserID string, details SpotifyPlaylistDetails) (string, error)
+	UpdatePlaylist(ctx context.Context, userID, spotifyPlaylistID string, details SpotifyPlaylistDetails) error
+	ReplaceTracks(ctx context.Context, userID, spotifyPlaylistID string, spotifyTrackIDs []string) error
+}
+
 type PlaylistService struct {
 	playlistRepo       repository.PlaylistRepository
 	trackRepo          repository.TrackRepository
 	userRepo           repository.UserRepository
 	recommendationRepo repository.RecommendationRepository
+	spotifyPublisher   SpotifyPlaylistPublisher
 }
 
+// NewPlaylistService builds the service. spotifyPublisher may be nil, in which
+// case exporting to Spotify is unavailable.
 func NewPlaylistService(playlistRepo repository.PlaylistRepository,
 	trackRepo repository.TrackRepository,
 	userRepo repository.UserRepository,
-	recommendationRepo repository.RecommendationRepository) *PlaylistService {
+	recommendationRepo repository.RecommendationRepository,
+	spotifyPublisher SpotifyPlaylistPublisher) *PlaylistService {
 	return &PlaylistService{
 		playlistRepo:       playlistRepo,
 		trackRepo:          trackRepo,
 		userRepo:           userRepo,
 		recommendationRepo: recommendationRepo,
+		spotifyPublisher:   spotifyPublisher,
 	}
 }
 
 func (s *PlaylistService) CreatePlaylist(ctx context.Context,
 	userID, name, description string,
-	mood valueObject.Mood) (*entity.Playlist, error) {
+	mood valueObject.Mood,
+	isPublic bool) (*entity.Playlist, error) {
+	if !valueObject.ValidMood(mood) {
+		return nil, ErrInvalidMood
+	}
+
 	_, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
 
 	playlist := entity.NewPlaylist(userID, name, description, mood)
+	playlist.IsPublic = isPublic
 
 	err = s.playlistRepo.Save(ctx, playlist)
 	if err != nil {
@@ -50,29 +79,137 @@ func (s *PlaylistService) CreatePlaylist(ctx context.Context,
 	}
 	return playlist, nil
 }
+
+// GetUserPlaylists returns the user's playlists and their tracks, keyed by
+// playlist ID.
 func (s *PlaylistService) GetUserPlaylists(
 	ctx context.Context,
 	userID string,
-) ([]*entity.Playlist, error) {
+) ([]*entity.Playlist, map[string][]*entity.Track, error) {
 	_, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, ErrUserNotFound
+		return nil, nil, ErrUserNotFound
 	}
 
 	playlists, err := s.playlistRepo.GetUserPlaylists(ctx, userID)
 	if err != nil {
+		return nil, nil, ErrPlaylistNotFound
+	}
+	return s.withTracks(ctx, playlists)
+}
+
+// GetPlaylist returns the playlist with its tracks if it belongs to the user or is public.
+func (s *PlaylistService) GetPlaylist(
+	ctx context.Context,
+	userID, playlistID string,
+) (*entity.Playlist, []*entity.Track, error) {
+	playlist, err := s.playlistRepo.GetByID(ctx, playlistID)
+	if err != nil {
+		return nil, nil, ErrPlaylistNotFound
+	}
+	if playlist.UserID != userID && !playlist.IsPublic {
+		return nil, nil, ErrPlaylistNotFound
+	}
+
+	tracks, err := s.playlistRepo.GetPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return playlist, tracks, nil
+}
+
+func (s *PlaylistService) UpdatePlaylist(
+	ctx context.Context,
+	userID, playlistID string,
+	name, description string,
+	mood valueObject.Mood,
+	isPublic bool,
+) (*entity.Playlist, error) {
+	if !valueObject.ValidMood(mood) {
+		return nil, ErrInvalidMood
+	}
+
+	playlist, err := s.getOwnedPlaylist(ctx, userID, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist.Name = name
+	playlist.Description = description
+	playlist.Mood = mood
+	playlist.IsPublic = isPublic
+
+	if err := s.playlistRepo.Update(ctx, playlist); err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// GetPublicPlaylists returns a page of public playlists, filtered by mood or
+// name, and their tracks, keyed by playlist ID.
+func (s *PlaylistService) GetPublicPlaylists(
+	ctx context.Context,
+	mood valueObject.Mood,
+	name string,
+	limit, offset int,
+) ([]*entity.Playlist, map[string][]*entity.Track, error) {
+	var playlists []*entity.Playlist
+	var err error
+	switch {
+	case mood != "":
+		if !valueObject.ValidMood(mood) {
+			return nil, nil, ErrInvalidMood
+		}
+		playlists, err = s.playlistRepo.FindByMood(ctx, mood, limit, offset)
+	case name != "":
+		playlists, err = s.playlistRepo.FindByName(ctx, name, limit, offset)
+	default:
+		playlists, err = s.playlistRepo.GetPublicPlaylists(ctx, limit, offset)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.withTracks(ctx, playlists)
+}
+
+func (s *PlaylistService) withTracks(
+	ctx context.Context,
+	playlists []*entity.Playlist,
+) ([]*entity.Playlist, map[string][]*entity.Track, error) {
+	playlistIDs := make([]string, len(playlists))
+	for i, playlist := range playlists {
+		playlistIDs[i] = playlist.ID
+	}
+
+	tracks, err := s.playlistRepo.GetPlaylistsTracks(ctx, playlistIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return playlists, tracks, nil
+}
+
+func (s *PlaylistService) getOwnedPlaylist(
+	ctx context.Context,
+	userID, playlistID string,
+) (*entity.Playlist, error) {
+	playlist, err := s.playlistRepo.GetByID(ctx, playlistID)
+	if err != nil {
+		return nil, ErrPlaylistNotFound
+	}
+	if playlist.UserID != userID {
 		return nil, ErrPlaylistNotFound
 	}
-	return playlists, err
+	return playlist, nil
 }
 
 func (s *PlaylistService) AddTrackToPlaylist(
 	ctx context.Context,
-	playlistID, trackID string,
+	userID, playlistID, trackID string,
 ) error {
-	playlist, err := s.playlistRepo.GetByID(ctx, playlistID)
+	playlist, err := s.getOwnedPlaylist(ctx, userID, playlistID)
 	if err != nil {
-		return ErrPlaylistNotFound
+		return err
 	}
 
 	_, err = s.trackRepo.GetByID(ctx, trackID)
@@ -85,11 +222,11 @@ func (s *PlaylistService) AddTrackToPlaylist(
 
 func (s *PlaylistService) RemoveTrackFromPlaylist(
 	ctx context.Context,
-	playlistID, trackID string,
+	userID, playlistID, trackID string,
 ) error {
-	_, err := s.playlistRepo.GetByID(ctx, playlistID)
+	_, err := s.getOwnedPlaylist(ctx, userID, playlistID)
 	if err != nil {
-		return ErrPlaylistNotFound
+		return err
 	}
 	_, err = s.trackRepo.GetByID(ctx, trackID)
 	if err != nil {
@@ -100,15 +237,11 @@ func (s *PlaylistService) RemoveTrackFromPlaylist(
 
 func (s *PlaylistService) GetPlaylistTracks(
 	ctx context.Context,
-	playlistID string,
+	userID, playlistID string,
 ) ([]*entity.Track, error) {
-	_, err := s.playlistRepo.GetByID(ctx, playlistID)
-	if err != nil {
-		return nil, ErrPlaylistNotFound
-	}
-	tracks, err := s.playlistRepo.GetPlaylistTracks(ctx, playlistID)
+	_, tracks, err := s.GetPlaylist(ctx, userID, playlistID)
 	if err != nil {
-		return nil, ErrTrackNotFound
+		return nil, err
 	}
 
 	return tracks, nil
@@ -116,11 +249,11 @@ func (s *PlaylistService) GetPlaylistTracks(
 
 func (s *PlaylistService) DeletePlaylist(
 	ctx context.Context,
-	playlistID string,
+	userID, playlistID string,
 ) error {
-	_, err := s.playlistRepo.GetByID(ctx, playlistID)
+	_, err := s.getOwnedPlaylist(ctx, userID, playlistID)
 	if err != nil {
-		return ErrPlaylistNotFound
+		return err
 	}
 
 	return s.playlistRepo.Delete(ctx, playlistID)
@@ -134,11 +267,16 @@ func (s *PlaylistService) CreatePlaylistFromRecommendation(
 ) (*entity.Playlist, error) {
 	recommendation, err := s.recommendationRepo.GetByID(ctx, recommendationID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recommendation by ID %s: %w", recommendationID, err)
+		return nil, fmt.Errorf("failed to get recommendation by ID %s: %w", recommendationID, ErrRecommendationNotFound)
+	}
+	if recommendation.UserID != userID {
+		return nil, ErrRecommendationNotFound
 	}
 
 	playlist := entity.NewPlaylist(userID, name, description, recommendation.Mood)
 
+	playlist.Weather = recommendation.Weather
+	playlist.TimeOfDay = recommendation.TimeOfDay
 	playlist.Tracks = recommendation.TrackIDs
 
 	if err := s.playlistRepo.Save(ctx, playlist); err != nil {
@@ -147,3 +285,78 @@ func (s *PlaylistService) CreatePlaylistFromRecommendation(
 
 	return playlist, nil
 }
+
+// ExportPlaylistToSpotify pushes the playlist's details and ordered tracks to
+// the owner's Spotify account. The first export creates the remote playlist
+// and remembers its ID; later exports overwrite that playlist in place, and
+// recreate it if it was deleted on Spotify. Tracks without a Spotify ID are
+// skipped.
+func (s *PlaylistService) ExportPlaylistToSpotify(
+	ctx context.Context,
+	userID, playlistID string,
+) (*entity.Playlist, error) {
+	if s.spotifyPublisher == nil {
+		return nil, ErrSpotifyExportUnavailable
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.SpotifyID == "" {
+		return nil, ErrSpotifyNotConnected
+	}
+
+	playlist, err := s.getOwnedPlaylist(ctx, userID, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := s.playlistRepo.GetPlaylistTracks(ctx, playlist.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	spotifyTrackIDs := make([]string, 0, len(tracks))
+	for _, track := range tracks {
+		if track.SpotifyID != "" {
+			spotifyTrackIDs = append(spotifyTrackIDs, track.SpotifyID)
+		}
+	}
+
+	details := SpotifyPlaylistDetails{
+		Name:        playlist.Name,
+		Description: playlist.Description,
+		Public:      playlist.IsPublic,
+	}
+
+	if playlist.SpotifyPlaylistID != "" {
+		err := s.spotifyPublisher.UpdatePlaylist(ctx, userID, playlist.SpotifyPlaylistID, details)
+		if err != nil {
+			if domainerr.KindOf(err) != domainerr.KindNotFound {
+				return nil, fmt.Errorf("failed to update spotify playlist: %w", err)
+			}
+			playlist.SpotifyPlaylistID = ""
+		}
+	}
+
+	if playlist.SpotifyPlaylistID == "" {
+		spotifyPlaylistID, err := s.spotifyPublisher.CreatePlaylist(ctx, userID, user.SpotifyID, details)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create spotify playlist: %w", err)
+		}
+
+		// Remember the remote playlist before adding tracks so a failed
+		// export is retried against it instead of creating a duplicate.
+		if err := s.playlistRepo.SetSpotifyPlaylistID(ctx, playlist.ID, spotifyPlaylistID); err != nil {
+			return nil, err
+		}
+		playlist.SpotifyPlaylistID = spotifyPlaylistID
+	}
+
+	if err := s.spotifyPublisher.ReplaceTracks(ctx, userID, playlist.SpotifyPlaylistID, spotifyTrackIDs); err != nil {
+		return nil, fmt.Errorf("failed to export playlist tracks: %w", err)
+	}
+
+	return playlist, nil
+}
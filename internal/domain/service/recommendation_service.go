@@ -2,29 +2,66 @@ package service
 
 import (
 	"context"
-	"errors"
 	"math/rand"
+	"sort"
+	"spotify_recommender/internal/domain/domainerr"
 	"spotify_recommender/internal/domain/entity"
 	"spotify_recommender/internal/domain/repository"
 	"spotify_recommender/internal/domain/valueObject"
 	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrNoRecommendations        = domainerr.NotFound("no_recommendations", "no suitable recommendations found")
+	ErrRecommendationNotFound   = domainerr.NotFound("recommendation_not_found", "recommendation not found")
+	ErrTrackNotInRecommendation = domainerr.NotFound("track_not_in_recommendation", "track is not part of the recommendation")
 )
 
-var ErrNoRecommendations = errors.New("no suitable recommendations found")
+const (
+	recommendationSize = 20
+	// catalogCandidateLimit is how many catalog tracks are considered for a
+	// recommendation; below minCatalogCandidates the fallback source is asked
+	// for fallbackCandidateLimit more.
+	catalogCandidateLimit  = 50
+	minCatalogCandidates   = recommendationSize
+	fallbackCandidateLimit = 50
+)
 
 type RecommendationService struct {
 	userRepo           repository.UserRepository
 	trackRepo          repository.TrackRepository
 	recommendationRepo repository.RecommendationRepository
+	catalog            CandidateSource
+	fallback           CandidateSource
+	seedSelector       *SeedSelector
+	calendar           *valueObject.Calendar
 }
 
+// NewRecommendationService builds the service. fallback supplies extra
+// candidates, such as Spotify recommendations, when the catalog is too thin;
+// it may be nil for offline deployments. seedSelector personalizes the
+// fallback request and may be nil, in which case default seeds are used.
+// calendar decides day types and may be nil for the default calendar.
 func NewRecommendationService(userRepo repository.UserRepository,
 	trackRepo repository.TrackRepository,
-	recommendationRepo repository.RecommendationRepository) *RecommendationService {
+	recommendationRepo repository.RecommendationRepository,
+	fallback CandidateSource,
+	seedSelector *SeedSelector,
+	calendar *valueObject.Calendar) *RecommendationService {
+	if calendar == nil {
+		calendar = valueObject.DefaultCalendar()
+	}
+
 	return &RecommendationService{
 		trackRepo:          trackRepo,
 		userRepo:           userRepo,
 		recommendationRepo: recommendationRepo,
+		catalog:            NewCatalogCandidateSource(trackRepo),
+		fallback:           fallback,
+		seedSelector:       seedSelector,
+		calendar:           calendar,
 	}
 }
 
@@ -33,20 +70,26 @@ func (s *RecommendationService) GetRecommendationsByContext(
 	userID string,
 	mood valueObject.Mood,
 	weather valueObject.Weather,
-	timeOfDay valueObject.TimeOfDay,
+	temporal valueObject.TemporalContext,
 ) (*entity.Recommendation, error) {
 
-	cachedRec, err := s.recommendationRepo.FindByContext(ctx, userID, mood, weather, timeOfDay)
+	cachedRec, err := s.recommendationRepo.FindByContext(ctx, userID, mood, weather, temporal)
 	if err == nil && cachedRec != nil && !cachedRec.IsExpired() {
 		return cachedRec, nil
 	}
 
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, err
+		return nil, ErrUserNotFound
 	}
 
-	tracks, err := s.trackRepo.FindByMoodWeatherTime(ctx, mood, weather, timeOfDay, 50)
+	tracks, err := s.gatherCandidates(ctx, CandidateRequest{
+		UserID:   userID,
+		Mood:     mood,
+		Weather:  weather,
+		Temporal: temporal,
+		Limit:    catalogCandidateLimit,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -61,8 +104,8 @@ func (s *RecommendationService) GetRecommendationsByContext(
 		filteredTracks = tracks
 	}
 
-	//делаем перемешку
-	recommendedTracks := s.selectRecommendedTracks(filteredTracks, 20)
+	rankedTracks := rankTracks(filteredTracks, mood, weather, temporal)
+	recommendedTracks := s.selectRecommendedTracks(rankedTracks, recommendationSize)
 	trackIDs := make([]string, len(recommendedTracks))
 
 	for i, track := range recommendedTracks {
@@ -73,7 +116,7 @@ func (s *RecommendationService) GetRecommendationsByContext(
 		userID,
 		mood,
 		weather,
-		timeOfDay,
+		temporal,
 		trackIDs,
 	)
 	err = s.recommendationRepo.Save(ctx, recommendation)
@@ -85,6 +128,105 @@ func (s *RecommendationService) GetRecommendationsByContext(
 
 }
 
+// gatherCandidates returns the catalog's candidates, topped up from the
+// fallback source when there are fewer than minCatalogCandidates. Fallback
+// tracks are stored in the catalog so they have IDs and are found locally
+// next time. A failing fallback only means fewer candidates.
+func (s *RecommendationService) gatherCandidates(ctx context.Context, request CandidateRequest) ([]*entity.Track, error) {
+	tracks, err := s.catalog.Candidates(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tracks) >= minCatalogCandidates || s.fallback == nil {
+		return tracks, nil
+	}
+
+	request.Limit = fallbackCandidateLimit
+	request.Seeds = valueObject.DefaultRecommendationSeeds()
+	if s.seedSelector != nil {
+		seeds, err := s.seedSelector.SelectSeeds(ctx, request.UserID, request.Mood)
+		if err == nil {
+			request.Seeds = seeds
+		}
+	}
+	// Nothing is left to seed from once the user's dislikes rule out even
+	// the default genres.
+	if request.Seeds.IsEmpty() {
+		return tracks, nil
+	}
+
+	extra, err := s.fallback.Candidates(ctx, request)
+	if err != nil {
+		log.Warn().Err(err).
+			Str("user_id", request.UserID).
+			Msg("failed to fetch fallback recommendation candidates")
+		return tracks, nil
+	}
+
+	seen := make(map[string]bool, len(tracks)+len(extra))
+	for _, track := range tracks {
+		seen[track.ID] = true
+	}
+
+	for _, track := range extra {
+		if err := s.trackRepo.Upsert(ctx, track); err != nil {
+			log.Warn().Err(err).
+				Str("spotify_id", track.SpotifyID).
+				Msg("failed to store fallback candidate")
+			continue
+		}
+		if !seen[track.ID] {
+			seen[track.ID] = true
+			tracks = append(tracks, track)
+		}
+	}
+
+	return tracks, nil
+}
+
+// rankTracks orders tracks by how well they fit the context, weighting mood
+// over weather over the time of day, day part and day type like the catalog
+// query, then by popularity.
+func rankTracks(
+	tracks []*entity.Track,
+	mood valueObject.Mood,
+	weather valueObject.Weather,
+	temporal valueObject.TemporalContext,
+) []*entity.Track {
+	scores := make(map[*entity.Track]int, len(tracks))
+	for _, track := range tracks {
+		score := 0
+		if track.AudioFeatures.MatchesMood(mood) {
+			score += 6
+		}
+		if track.AudioFeatures.MatchesWeather(weather) {
+			score += 4
+		}
+		if track.AudioFeatures.MatchesTimeOfDay(temporal.TimeOfDay) {
+			score++
+		}
+		if track.AudioFeatures.MatchesDayPart(temporal.DayPart) {
+			score++
+		}
+		if track.AudioFeatures.MatchesDayType(temporal.DayType, temporal.DayPart) {
+			score++
+		}
+		scores[track] = score
+	}
+
+	ranked := make([]*entity.Track, len(tracks))
+	copy(ranked, tracks)
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if scores[ranked[i]] == scores[ranked[j]] {
+			return ranked[i].Popularity > ranked[j].Popularity
+		}
+		return scores[ranked[i]] > scores[ranked[j]]
+	})
+	return ranked
+}
+
 func (s *RecommendationService) filterTracksByUserPreferences(
 	tracks []*entity.Track,
 	preferences entity.Preferences,
@@ -100,34 +242,102 @@ func (s *RecommendationService) filterTracksByUserPreferences(
 	return filtered
 }
 
+// selectRecommendedTracks picks count tracks at random from the best ranked
+// ones, so repeated requests vary without drifting far from the best fits,
+// and returns them in rank order.
 func (s *RecommendationService) selectRecommendedTracks(
-	tracks []*entity.Track,
+	ranked []*entity.Track,
 	count int,
 ) []*entity.Track {
-	if len(tracks) <= count {
-		return tracks
+	if len(ranked) <= count {
+		return ranked
 	}
 
-	shuffled := make([]*entity.Track, len(tracks))
-	copy(shuffled, tracks)
+	pool := min(len(ranked), count*2)
+	positions := make([]int, pool)
+	for i := range positions {
+		positions[i] = i
+	}
 
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	r.Shuffle(len(shuffled), func(i, j int) {
-		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	r.Shuffle(len(positions), func(i, j int) {
+		positions[i], positions[j] = positions[j], positions[i]
 	})
-	return shuffled[:count]
+
+	positions = positions[:count]
+	sort.Ints(positions)
+
+	selected := make([]*entity.Track, count)
+	for i, position := range positions {
+		selected[i] = ranked[position]
+	}
+	return selected
 }
 
+// GetRecommendationsByMood recommends for the mood in sunny weather, right
+// now in the user's time zone or, without one, the server's.
 func (s *RecommendationService) GetRecommendationsByMood(
 	ctx context.Context,
 	userID string,
 	mood valueObject.Mood,
 ) (*entity.Recommendation, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	now := time.Now()
+	if location, ok := user.Location(); ok {
+		now = now.In(location)
+	}
+
 	weather := valueObject.WeatherSunny
-	timeOfDay := valueObject.GetCurrentTimeOfToday()
+	temporal := s.calendar.TemporalContext(now)
+
+	return s.GetRecommendationsByContext(ctx, userID, mood, weather, temporal)
+}
+
+func (s *RecommendationService) GetRecommendation(
+	ctx context.Context,
+	userID string,
+	recommendationID string,
+) (*entity.Recommendation, error) {
+	recommendation, err := s.recommendationRepo.GetByID(ctx, recommendationID)
+	if err != nil {
+		return nil, ErrRecommendationNotFound
+	}
+	if recommendation.UserID != userID {
+		return nil, ErrRecommendationNotFound
+	}
+	return recommendation, nil
+}
+
+func (s *RecommendationService) GetUserRecommendations(
+	ctx context.Context,
+	userID string,
+) ([]*entity.Recommendation, error) {
+	return s.recommendationRepo.GetForUser(ctx, userID)
+}
 
-	return s.GetRecommendationsByContext(ctx, userID, mood, weather, timeOfDay)
+func (s *RecommendationService) SaveRecommendationFeedback(
+	ctx context.Context,
+	userID string,
+	recommendationID string,
+	trackID string,
+	liked bool,
+) error {
+	recommendation, err := s.GetRecommendation(ctx, userID, recommendationID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range recommendation.TrackIDs {
+		if id == trackID {
+			return s.SaveUserTrackInteraction(ctx, userID, trackID, liked)
+		}
+	}
+	return ErrTrackNotInRecommendation
 }
 
 func (s *RecommendationService) SaveUserTrackInteraction(
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
+	"spotify_recommender/internal/domain/valueObject"
+	"strings"
+	"time"
+)
+
+const (
+	// likedTracksSampleSize is how many of the user's most recently liked
+	// tracks are considered as track seeds.
+	likedTracksSampleSize = 50
+	// recentPlaysSampleSize is how many of the user's recent plays within
+	// recentPlaysWindow are considered as track seeds.
+	recentPlaysSampleSize = 50
+	recentPlaysWindow     = 30 * 24 * time.Hour
+)
+
+// SeedSelector picks Spotify recommendation seeds that reflect a user's
+// taste: their favorite genres, top artists, and the tracks they like or
+// listen to, skipping anything tagged with a disliked genre.
+type SeedSelector struct {
+	userRepo    repository.UserRepository
+	historyRepo repository.ListeningHistoryRepository
+	trackRepo   repository.TrackRepository
+}
+
+func NewSeedSelector(
+	userRepo repository.UserRepository,
+	historyRepo repository.ListeningHistoryRepository,
+	trackRepo repository.TrackRepository,
+) *SeedSelector {
+	return &SeedSelector{
+		userRepo:    userRepo,
+		historyRepo: historyRepo,
+		trackRepo:   trackRepo,
+	}
+}
+
+// SelectSeeds returns up to valueObject.MaxRecommendationSeeds seeds for the
+// user. Sources are taken in turn so no single one crowds out the others;
+// tracks matching the mood are preferred. Users we know nothing about
+// get the default genres, minus any they dislike, which may leave no seeds.
+func (s *SeedSelector) SelectSeeds(
+	ctx context.Context,
+	userID string,
+	mood valueObject.Mood,
+) (valueObject.RecommendationSeeds, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return valueObject.RecommendationSeeds{}, ErrUserNotFound
+	}
+
+	disliked := make(map[string]bool, len(user.Preferences.DislikedGenres))
+	for _, genre := range user.Preferences.DislikedGenres {
+		disliked[normalizeGenre(genre)] = true
+	}
+
+	genres := s.favoriteGenres(user.Preferences.FavoriteGenres, disliked)
+	artistIDs := s.topArtistIDs(ctx, userID, disliked)
+	trackIDs := s.trackIDs(ctx, userID, mood, disliked)
+
+	var seeds valueObject.RecommendationSeeds
+	for seeds.Len() < valueObject.MaxRecommendationSeeds {
+		added := false
+		if len(genres) > 0 {
+			seeds.Genres = append(seeds.Genres, genres[0])
+			genres, added = genres[1:], true
+		}
+		if len(artistIDs) > 0 && seeds.Len() < valueObject.MaxRecommendationSeeds {
+			seeds.ArtistIDs = append(seeds.ArtistIDs, artistIDs[0])
+			artistIDs, added = artistIDs[1:], true
+		}
+		if len(trackIDs) > 0 && seeds.Len() < valueObject.MaxRecommendationSeeds {
+			seeds.TrackIDs = append(seeds.TrackIDs, trackIDs[0])
+			trackIDs, added = trackIDs[1:], true
+		}
+		if !added {
+			break
+		}
+	}
+
+	if seeds.IsEmpty() {
+		for _, genre := range valueObject.DefaultRecommendationSeeds().Genres {
+			if !disliked[genre] {
+				seeds.Genres = append(seeds.Genres, genre)
+			}
+		}
+	}
+
+	return seeds, nil
+}
+
+func (s *SeedSelector) favoriteGenres(favorites []string, disliked map[string]bool) []string {
+	var genres []string
+	seen := make(map[string]bool, len(favorites))
+	for _, genre := range favorites {
+		genre = normalizeGenre(genre)
+		if genre == "" || disliked[genre] || seen[genre] {
+			continue
+		}
+		seen[genre] = true
+		genres = append(genres, genre)
+	}
+	return genres
+}
+
+// topArtistIDs returns the user's top artists, most recent time range first.
+// History is best effort: users who never synced simply have none.
+func (s *SeedSelector) topArtistIDs(ctx context.Context, userID string, disliked map[string]bool) []string {
+	var artistIDs []string
+	seen := make(map[string]bool)
+
+	for _, timeRange := range entity.AllTopTimeRanges() {
+		events, err := s.historyRepo.GetTopItems(ctx, userID, entity.PlayEventTopArtist, timeRange)
+		if err != nil {
+			continue
+		}
+
+		for _, event := range events {
+			if event.SpotifyArtistID == "" || seen[event.SpotifyArtistID] || hasDislikedGenre(event.Genres, disliked) {
+				continue
+			}
+			seen[event.SpotifyArtistID] = true
+			artistIDs = append(artistIDs, event.SpotifyArtistID)
+		}
+	}
+
+	return artistIDs
+}
+
+// trackIDs returns the Spotify IDs of the tracks the user liked recently,
+// then of their top tracks and recent plays from the synced listening
+// history, those matching the mood first.
+func (s *SeedSelector) trackIDs(
+	ctx context.Context,
+	userID string,
+	mood valueObject.Mood,
+	disliked map[string]bool,
+) []string {
+	tracks, _, err := s.userRepo.GetUserLikedTracks(ctx, userID, likedTracksSampleSize, 0)
+	if err != nil {
+		tracks = nil
+	}
+	tracks = append(tracks, s.listenedTracks(ctx, userID)...)
+
+	var matching, others []string
+	seen := make(map[string]bool, len(tracks))
+	for _, track := range tracks {
+		if track.SpotifyID == "" || seen[track.SpotifyID] || hasDislikedGenre(track.Genres, disliked) {
+			continue
+		}
+		seen[track.SpotifyID] = true
+		if track.AudioFeatures.MatchesMood(mood) {
+			matching = append(matching, track.SpotifyID)
+		} else {
+			others = append(others, track.SpotifyID)
+		}
+	}
+
+	return append(matching, others...)
+}
+
+// listenedTracks returns the user's top tracks, most recent time range
+// first, then their recent plays. Like topArtistIDs it is best effort.
+func (s *SeedSelector) listenedTracks(ctx context.Context, userID string) []*entity.Track {
+	var events []*entity.PlayEvent
+	for _, timeRange := range entity.AllTopTimeRanges() {
+		topTracks, err := s.historyRepo.GetTopItems(ctx, userID, entity.PlayEventTopTrack, timeRange)
+		if err != nil {
+			continue
+		}
+		events = append(events, topTracks...)
+	}
+	recentPlays, err := s.historyRepo.GetRecentPlays(ctx, userID, time.Now().Add(-recentPlaysWindow), recentPlaysSampleSize)
+	if err == nil {
+		events = append(events, recentPlays...)
+	}
+
+	var trackIDs []string
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		if event.TrackID == "" || seen[event.TrackID] {
+			continue
+		}
+		seen[event.TrackID] = true
+		trackIDs = append(trackIDs, event.TrackID)
+	}
+	if len(trackIDs) == 0 {
+		return nil
+	}
+
+	found, err := s.trackRepo.GetByIDs(ctx, trackIDs)
+	if err != nil {
+		return nil
+	}
+	byID := make(map[string]*entity.Track, len(found))
+	for _, track := range found {
+		byID[track.ID] = track
+	}
+
+	tracks := make([]*entity.Track, 0, len(found))
+	for _, trackID := range trackIDs {
+		if track, ok := byID[trackID]; ok {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks
+}
+
+// hasDislikedGenre reports whether any genre contains a disliked one as whole
+// words, so disliking "pop" also rules out "dance-pop" artists.
+func hasDislikedGenre(genres []string, disliked map[string]bool) bool {
+	for _, genre := range genres {
+		padded := "-" + normalizeGenre(genre) + "-"
+		for dislikedGenre := range disliked {
+			if strings.Contains(padded, "-"+dislikedGenre+"-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeGenre converts a genre name to Spotify's seed form, e.g.
+// "Hip Hop" to "hip-hop".
+func normalizeGenre(genre string) string {
+	return strings.Join(strings.Fields(strings.ToLower(genre)), "-")
+}
@@ -81,3 +81,43 @@ func (af *AudioFeatures) MatchesTimeOfDay(time TimeOfDay) bool {
 		return true
 	}
 }
+
+func (af *AudioFeatures) MatchesDayPart(part DayPart) bool {
+	switch part {
+	case DayPartDawn:
+		return af.Energy < 0.5 && af.Acousticness > 0.4
+	case DayPartMorningCommute:
+		return af.Valence > 0.5 && af.Energy > 0.5 && af.Energy < 0.85
+	case DayPartWorkHours:
+		return af.Energy > 0.3 && af.Energy < 0.7 && af.Speechiness < 0.2
+	case DayPartEveningCommute:
+		return af.Valence > 0.4 && af.Energy > 0.5
+	case DayPartMorning:
+		return af.Valence > 0.5 && af.Energy < 0.6 && af.Acousticness > 0.3
+	case DayPartAfternoon:
+		return af.Valence > 0.5 && af.Danceability > 0.5
+	case DayPartEvening:
+		return af.Energy > 0.3 && af.Energy < 0.8
+	case DayPartLateNight:
+		return af.Energy < 0.4 && af.Acousticness > 0.5
+	default:
+		return true
+	}
+}
+
+// MatchesDayType tells whether the track suits the day part on a day of the
+// given type: nights before a rest day are for going out, nights before a
+// workday for winding down, rest days are easygoing and workdays call for
+// steady music with few vocals in the way.
+func (af *AudioFeatures) MatchesDayType(dayType DayType, part DayPart) bool {
+	switch {
+	case dayType.IsRestDay() && part.IsNight():
+		return af.Danceability > 0.7 && af.Energy > 0.7
+	case part.IsNight():
+		return af.Energy < 0.6
+	case dayType.IsRestDay():
+		return af.Valence > 0.6
+	default:
+		return af.Speechiness < 0.33 && af.Energy > 0.4 && af.Energy < 0.8
+	}
+}
@@ -0,0 +1,77 @@
+package valueObject
+
+import "time"
+
+// Holiday is a public holiday. A zero Year makes it fall on the same date
+// every year.
+type Holiday struct {
+	Year  int
+	Month time.Month
+	Day   int
+	Name  string
+}
+
+// Calendar knows which days are rest days: the weekend days of the week and
+// the public holidays.
+type Calendar struct {
+	weekend  map[time.Weekday]bool
+	holidays map[Holiday]string
+}
+
+func NewCalendar(weekend []time.Weekday, holidays []Holiday) *Calendar {
+	calendar := &Calendar{
+		weekend:  make(map[time.Weekday]bool, len(weekend)),
+		holidays: make(map[Holiday]string, len(holidays)),
+	}
+	for _, day := range weekend {
+		calendar.weekend[day] = true
+	}
+	for _, holiday := range holidays {
+		calendar.holidays[Holiday{Year: holiday.Year, Month: holiday.Month, Day: holiday.Day}] = holiday.Name
+	}
+	return calendar
+}
+
+// DefaultCalendar has a Saturday and Sunday weekend and no holidays.
+func DefaultCalendar() *Calendar {
+	return NewCalendar([]time.Weekday{time.Saturday, time.Sunday}, nil)
+}
+
+// Holiday returns the name of the holiday on t's calendar day, in t's
+// location, and reports whether there is one.
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	year, month, day := t.Date()
+	if name, ok := c.holidays[Holiday{Year: year, Month: month, Day: day}]; ok {
+		return name, true
+	}
+	name, ok := c.holidays[Holiday{Month: month, Day: day}]
+	return name, ok
+}
+
+// DayType returns the type of t's calendar day, in t's location.
+func (c *Calendar) DayType(t time.Time) DayType {
+	if _, ok := c.Holiday(t); ok {
+		return DayTypeHoliday
+	}
+	if c.weekend[t.Weekday()] {
+		return DayTypeWeekend
+	}
+	return DayTypeWorkday
+}
+
+// TemporalContext returns the temporal context of t, judged by the clock in
+// t's location. An evening or night takes the day type of the day it leads
+// into.
+func (c *Calendar) TemporalContext(t time.Time) TemporalContext {
+	dayType := c.DayType(t)
+	part := DayPartAt(t, dayType)
+	if part.IsNight() && t.Hour() >= eveningStartHour {
+		dayType = c.DayType(t.AddDate(0, 0, 1))
+	}
+
+	return TemporalContext{
+		TimeOfDay: TimeOfDayAt(t),
+		DayPart:   part,
+		DayType:   dayType,
+	}
+}
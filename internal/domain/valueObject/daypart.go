@@ -0,0 +1,129 @@
+package valueObject
+
+import "time"
+
+// DayPart is a finer division of the day than TimeOfDay, following the
+// rhythm of the day: commutes and work hours on workdays, a free morning and
+// afternoon on rest days.
+type DayPart string
+
+const (
+	DayPartDawn           DayPart = "dawn"
+	DayPartMorningCommute DayPart = "morning_commute"
+	DayPartWorkHours      DayPart = "work_hours"
+	DayPartEveningCommute DayPart = "evening_commute"
+	DayPartMorning        DayPart = "morning"
+	DayPartAfternoon      DayPart = "afternoon"
+	DayPartEvening        DayPart = "evening"
+	DayPartLateNight      DayPart = "late_night"
+)
+
+// DayType tells working days from weekends and public holidays.
+type DayType string
+
+const (
+	DayTypeWorkday DayType = "workday"
+	DayTypeWeekend DayType = "weekend"
+	DayTypeHoliday DayType = "holiday"
+)
+
+// eveningStartHour is when the evening starts on any day. From then on the
+// night counts as part of the day it leads into, so Friday night is weekend
+// and Sunday night is not.
+const eveningStartHour = 19
+
+// TemporalContext is everything about a moment that shapes what fits it
+// musically.
+type TemporalContext struct {
+	TimeOfDay TimeOfDay `json:"time_of_day"`
+	DayPart   DayPart   `json:"day_part"`
+	DayType   DayType   `json:"day_type"`
+}
+
+func ValidDayPart(part DayPart) bool {
+	for _, p := range AllDayParts() {
+		if part == p {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidDayType(dayType DayType) bool {
+	switch dayType {
+	case DayTypeWorkday, DayTypeWeekend, DayTypeHoliday:
+		return true
+	default:
+		return false
+	}
+}
+
+func AllDayParts() []DayPart {
+	return []DayPart{
+		DayPartDawn, DayPartMorningCommute, DayPartWorkHours, DayPartEveningCommute,
+		DayPartMorning, DayPartAfternoon, DayPartEvening, DayPartLateNight,
+	}
+}
+
+// IsRestDay reports whether the day is a weekend day or a holiday.
+func (d DayType) IsRestDay() bool {
+	return d == DayTypeWeekend || d == DayTypeHoliday
+}
+
+// IsNight reports whether the day part is the evening or later, when the
+// day type is that of the following day.
+func (p DayPart) IsNight() bool {
+	return p == DayPartEvening || p == DayPartLateNight
+}
+
+// DayPartAt returns the part of the day t falls in, judged by the clock in
+// t's own location, on a day of the given type.
+func DayPartAt(t time.Time, dayType DayType) DayPart {
+	hour := t.Hour()
+
+	switch {
+	case hour >= 23 || hour < 5:
+		return DayPartLateNight
+	case hour < 7:
+		return DayPartDawn
+	case hour >= eveningStartHour:
+		return DayPartEvening
+	}
+
+	if dayType.IsRestDay() {
+		if hour < 12 {
+			return DayPartMorning
+		}
+		return DayPartAfternoon
+	}
+
+	switch {
+	case hour < 9:
+		return DayPartMorningCommute
+	case hour < 17:
+		return DayPartWorkHours
+	default:
+		return DayPartEveningCommute
+	}
+}
+
+// DayPartFor returns the day part that best stands for a time of day on a day
+// of the given type, for when only the time of day is known.
+func DayPartFor(timeOfDay TimeOfDay, dayType DayType) DayPart {
+	switch timeOfDay {
+	case TimeOfDayMorning:
+		if dayType.IsRestDay() {
+			return DayPartMorning
+		}
+		return DayPartMorningCommute
+	case TimeOfDayAfternoon:
+		if dayType.IsRestDay() {
+			return DayPartAfternoon
+		}
+		return DayPartWorkHours
+	case TimeOfDayEvening:
+		return DayPartEvening
+	default:
+		return DayPartLateNight
+	}
+}
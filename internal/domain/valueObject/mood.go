@@ -1,4 +1,4 @@
-package valueobject
+package valueObject
 
 type Mood string
 
@@ -0,0 +1,29 @@
+package valueObject
+
+// MaxRecommendationSeeds is the most seeds Spotify accepts, across tracks,
+// artists and genres, in one recommendations request.
+const MaxRecommendationSeeds = 5
+
+// RecommendationSeeds are the Spotify track IDs, artist IDs and genres a
+// recommendations request is based on.
+type RecommendationSeeds struct {
+	TrackIDs  []string `json:"track_ids,omitempty"`
+	ArtistIDs []string `json:"artist_ids,omitempty"`
+	Genres    []string `json:"genres,omitempty"`
+}
+
+// DefaultRecommendationSeeds are broad genres used when nothing is known
+// about the listener.
+func DefaultRecommendationSeeds() RecommendationSeeds {
+	return RecommendationSeeds{
+		Genres: []string{"pop", "rock", "electronic", "classical", "hip-hop"},
+	}
+}
+
+func (s RecommendationSeeds) Len() int {
+	return len(s.TrackIDs) + len(s.ArtistIDs) + len(s.Genres)
+}
+
+func (s RecommendationSeeds) IsEmpty() bool {
+	return s.Len() == 0
+}
@@ -0,0 +1,53 @@
+package valueObject
+
+import (
+	"math"
+	"time"
+)
+
+// sunriseZenith is the zenith angle of the sun's center at sunrise and
+// sunset, accounting for refraction and the size of the solar disk.
+const sunriseZenith = 90.833
+
+// SunTimes is when the sun rises, culminates and sets on a day at a place.
+type SunTimes struct {
+	Sunrise   time.Time
+	SolarNoon time.Time
+	Sunset    time.Time
+}
+
+// SunTimesOn returns the sun times on the calendar day of date, in date's
+// location, at the coordinates. It reports false during polar day or polar
+// night, when the sun does not rise or set. Times are those of the NOAA
+// approximation, within a couple of minutes of the real ones.
+func SunTimesOn(date time.Time, latitude, longitude float64) (SunTimes, bool) {
+	year, month, day := date.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	// Fractional year, in radians, at noon of the day.
+	gamma := 2 * math.Pi / 365 * float64(date.YearDay()-1)
+
+	equationOfTime := 229.18 * (0.000075 + 0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma))
+	declination := 0.006918 - 0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+
+	lat := latitude * math.Pi / 180
+	cosHourAngle := math.Cos(sunriseZenith*math.Pi/180)/(math.Cos(lat)*math.Cos(declination)) -
+		math.Tan(lat)*math.Tan(declination)
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return SunTimes{}, false
+	}
+	hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+
+	// Minutes after midnight UTC.
+	at := func(minutes float64) time.Time {
+		return midnight.Add(time.Duration(minutes * float64(time.Minute))).In(date.Location())
+	}
+	return SunTimes{
+		Sunrise:   at(720 - 4*(longitude+hourAngle) - equationOfTime),
+		SolarNoon: at(720 - 4*longitude - equationOfTime),
+		Sunset:    at(720 - 4*(longitude-hourAngle) - equationOfTime),
+	}, true
+}
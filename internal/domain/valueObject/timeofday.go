@@ -11,8 +11,37 @@ const (
 	TimeOfDayNight     TimeOfDay = "night"
 )
 
+// TimeOfDayMode is how the time of day is derived from a moment.
+type TimeOfDayMode string
+
+const (
+	// TimeOfDayModeClock splits the day at fixed hours of the local clock.
+	TimeOfDayModeClock TimeOfDayMode = "clock"
+	// TimeOfDayModeAstronomical splits the day around local sunrise, solar
+	// noon and sunset.
+	TimeOfDayModeAstronomical TimeOfDayMode = "astronomical"
+)
+
+// Astronomical boundaries: morning starts with the first light before
+// sunrise, evening an hour before sunset, and night once dusk has passed.
+const (
+	morningBeforeSunrise = time.Hour
+	eveningBeforeSunset  = time.Hour
+	nightAfterSunset     = 3 * time.Hour
+)
+
+func ValidTimeOfDayMode(mode TimeOfDayMode) bool {
+	return mode == TimeOfDayModeClock || mode == TimeOfDayModeAstronomical
+}
+
 func GetCurrentTimeOfToday() TimeOfDay {
-	hour := time.Now().Hour()
+	return TimeOfDayAt(time.Now())
+}
+
+// TimeOfDayAt returns the part of the day t falls in, judged by the clock in
+// t's own location.
+func TimeOfDayAt(t time.Time) TimeOfDay {
+	hour := t.Hour()
 
 	switch {
 	case hour >= 5 && hour < 12:
@@ -26,6 +55,30 @@ func GetCurrentTimeOfToday() TimeOfDay {
 	}
 }
 
+// AstronomicalTimeOfDayAt returns the part of the day t falls in at the
+// coordinates, judged by the sun on t's calendar day in t's location.
+// Evening lasts until midnight at the latest. During polar day or night it
+// falls back to the clock.
+func AstronomicalTimeOfDayAt(t time.Time, latitude, longitude float64) TimeOfDay {
+	sun, ok := SunTimesOn(t, latitude, longitude)
+	if !ok {
+		return TimeOfDayAt(t)
+	}
+
+	switch {
+	case t.Before(sun.Sunrise.Add(-morningBeforeSunrise)):
+		return TimeOfDayNight
+	case t.Before(sun.SolarNoon):
+		return TimeOfDayMorning
+	case t.Before(sun.Sunset.Add(-eveningBeforeSunset)):
+		return TimeOfDayAfternoon
+	case t.Before(sun.Sunset.Add(nightAfterSunset)):
+		return TimeOfDayEvening
+	default:
+		return TimeOfDayNight
+	}
+}
+
 func AllTimesOfDay() []TimeOfDay {
 	return []TimeOfDay{
 		TimeOfDayMorning, TimeOfDayAfternoon,
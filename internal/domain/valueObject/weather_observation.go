@@ -0,0 +1,100 @@
+package valueObject
+
+// WeatherUnits is the unit system of a weather observation, named as
+// OpenWeatherMap names them.
+type WeatherUnits string
+
+const (
+	// WeatherUnitsStandard uses kelvin and metres per second.
+	WeatherUnitsStandard WeatherUnits = "standard"
+	// WeatherUnitsMetric uses degrees Celsius and metres per second.
+	WeatherUnitsMetric WeatherUnits = "metric"
+	// WeatherUnitsImperial uses degrees Fahrenheit and miles per hour.
+	WeatherUnitsImperial WeatherUnits = "imperial"
+)
+
+func ValidWeatherUnits(units WeatherUnits) bool {
+	switch units {
+	case WeatherUnitsStandard, WeatherUnitsMetric, WeatherUnitsImperial:
+		return true
+	default:
+		return false
+	}
+}
+
+// Classification thresholds, in degrees Celsius, metres per second and
+// percent of sky covered.
+const (
+	hotFeelsLikeCelsius  = 30.0
+	coldFeelsLikeCelsius = 0.0
+	windyWindSpeed       = 10.0
+	cloudyCloudCover     = 60
+)
+
+// WeatherObservation is the weather measured at a place and time.
+// ConditionCode is an OpenWeatherMap condition code; temperatures and wind
+// speed are in Units.
+type WeatherObservation struct {
+	ConditionCode int          `json:"condition_code"`
+	Temperature   float64      `json:"temperature"`
+	FeelsLike     float64      `json:"feels_like"`
+	WindSpeed     float64      `json:"wind_speed"`
+	Humidity      int          `json:"humidity"`
+	CloudCover    int          `json:"cloud_cover"`
+	Units         WeatherUnits `json:"units"`
+}
+
+// FeelsLikeCelsius returns the apparent temperature in degrees Celsius.
+func (o WeatherObservation) FeelsLikeCelsius() float64 {
+	switch o.Units {
+	case WeatherUnitsStandard:
+		return o.FeelsLike - 273.15
+	case WeatherUnitsImperial:
+		return (o.FeelsLike - 32) * 5 / 9
+	default:
+		return o.FeelsLike
+	}
+}
+
+// WindSpeedMetresPerSecond returns the wind speed in metres per second.
+func (o WeatherObservation) WindSpeedMetresPerSecond() float64 {
+	if o.Units == WeatherUnitsImperial {
+		return o.WindSpeed * 0.44704
+	}
+	return o.WindSpeed
+}
+
+// Classify derives the weather context from the observation. Storms,
+// squalls and precipitation decide it outright; otherwise strong wind, then
+// an apparent temperature that is hot or cold, then fog and finally cloud
+// cover do.
+func (o WeatherObservation) Classify() Weather {
+	switch {
+	case o.ConditionCode == 781:
+		return WeatherStormy // tornado
+	case o.ConditionCode == 771:
+		return WeatherWindy // squalls
+	}
+
+	condition := MapFromOpenWeather(o.ConditionCode)
+	switch condition {
+	case WeatherStormy, WeatherRainy, WeatherSnowy:
+		return condition
+	}
+
+	feelsLike := o.FeelsLikeCelsius()
+	switch {
+	case o.WindSpeedMetresPerSecond() >= windyWindSpeed:
+		return WeatherWindy
+	case feelsLike >= hotFeelsLikeCelsius:
+		return WeatherHot
+	case feelsLike <= coldFeelsLikeCelsius:
+		return WeatherCold
+	case condition == WeatherFoggy:
+		return WeatherFoggy
+	case o.CloudCover >= cloudyCloudCover || o.ConditionCode >= 803:
+		return WeatherCloudy
+	default:
+		return WeatherSunny
+	}
+}
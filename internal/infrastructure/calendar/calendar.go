@@ -0,0 +1,93 @@
+// Package calendar loads the weekend days and public holidays that decide
+// day types from a local JSON file, such as holidays.example.json.
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"spotify_recommender/internal/domain/valueObject"
+	"strings"
+	"time"
+)
+
+const (
+	// dateLayout is the layout of a holiday on one date only.
+	dateLayout = "2006-01-02"
+	// recurringDateLayout is the layout of a holiday on the same date every
+	// year.
+	recurringDateLayout = "01-02"
+)
+
+// calendarFile is the format of a calendar file. Weekend names days of the
+// week and defaults to Saturday and Sunday.
+type calendarFile struct {
+	Weekend  []string `json:"weekend"`
+	Holidays []struct {
+		Date string `json:"date"`
+		Name string `json:"name"`
+	} `json:"holidays"`
+}
+
+func Load(path string) (*valueObject.Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar file: %w", err)
+	}
+
+	calendar, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load calendar %s: %w", path, err)
+	}
+	return calendar, nil
+}
+
+func Parse(data []byte) (*valueObject.Calendar, error) {
+	var file calendarFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse calendar: %w", err)
+	}
+
+	weekend := []time.Weekday{time.Saturday, time.Sunday}
+	if file.Weekend != nil {
+		weekend = make([]time.Weekday, len(file.Weekend))
+		for i, name := range file.Weekend {
+			day, err := parseWeekday(name)
+			if err != nil {
+				return nil, err
+			}
+			weekend[i] = day
+		}
+	}
+
+	holidays := make([]valueObject.Holiday, len(file.Holidays))
+	for i, entry := range file.Holidays {
+		holiday, err := parseHoliday(entry.Date)
+		if err != nil {
+			return nil, err
+		}
+		holiday.Name = entry.Name
+		holidays[i] = holiday
+	}
+
+	return valueObject.NewCalendar(weekend, holidays), nil
+}
+
+func parseWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekend day %q", name)
+}
+
+func parseHoliday(date string) (valueObject.Holiday, error) {
+	if t, err := time.Parse(dateLayout, date); err == nil {
+		return valueObject.Holiday{Year: t.Year(), Month: t.Month(), Day: t.Day()}, nil
+	}
+	if t, err := time.Parse(recurringDateLayout, date); err == nil {
+		return valueObject.Holiday{Month: t.Month(), Day: t.Day()}, nil
+	}
+	return valueObject.Holiday{}, fmt.Errorf("invalid holiday date %q, want YYYY-MM-DD or MM-DD", date)
+}
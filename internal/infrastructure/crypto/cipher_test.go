@@ -0,0 +1,63 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCipherBindsAssociatedData(t *testing.T) {
+	cipher, err := NewCipher(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	ciphertext, err := cipher.Encrypt("refresh-token", []byte("user-1"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		ciphertext     string
+		associatedData []byte
+		want           string
+		wantErr        error
+	}{
+		{
+			name:           "same associated data",
+			ciphertext:     ciphertext,
+			associatedData: []byte("user-1"),
+			want:           "refresh-token",
+		},
+		{
+			name:           "other associated data",
+			ciphertext:     ciphertext,
+			associatedData: []byte("user-2"),
+			wantErr:        ErrInvalidCiphertext,
+		},
+		{
+			name:       "no associated data",
+			ciphertext: ciphertext,
+			wantErr:    ErrInvalidCiphertext,
+		},
+		{
+			name:           "not base64",
+			ciphertext:     "not base64!",
+			associatedData: []byte("user-1"),
+			wantErr:        ErrInvalidCiphertext,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cipher.Decrypt(tt.ciphertext, tt.associatedData)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Decrypt() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Decrypt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"spotify_recommender/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type LibraryImportRepository struct {
+	db *sqlx.DB
+}
+
+func NewLibraryImportRepository(db *sqlx.DB) *LibraryImportRepository {
+	return &LibraryImportRepository{
+		db: db,
+	}
+}
+
+type libraryImportModel struct {
+	ID                string         `db:"id"`
+	UserID            string         `db:"user_id"`
+	Status            string         `db:"status"`
+	Phase             string         `db:"phase"`
+	SavedTracksOffset int            `db:"saved_tracks_offset"`
+	SavedTracksTotal  int            `db:"saved_tracks_total"`
+	PlaylistsOffset   int            `db:"playlists_offset"`
+	PlaylistsTotal    int            `db:"playlists_total"`
+	TracksImported    int            `db:"tracks_imported"`
+	PlaylistsImported int            `db:"playlists_imported"`
+	Error             sql.NullString `db:"error"`
+	StartedAt         time.Time      `db:"started_at"`
+	UpdatedAt         time.Time      `db:"updated_at"`
+	CompletedAt       sql.NullTime   `db:"completed_at"`
+}
+
+func (m *libraryImportModel) toEntity() *entity.LibraryImport {
+	libraryImport := &entity.LibraryImport{
+		ID:                m.ID,
+		UserID:            m.UserID,
+		Status:            entity.LibraryImportStatus(m.Status),
+		Phase:             entity.LibraryImportPhase(m.Phase),
+		SavedTracksOffset: m.SavedTracksOffset,
+		SavedTracksTotal:  m.SavedTracksTotal,
+		PlaylistsOffset:   m.PlaylistsOffset,
+		PlaylistsTotal:    m.PlaylistsTotal,
+		TracksImported:    m.TracksImported,
+		PlaylistsImported: m.PlaylistsImported,
+		StartedAt:         m.StartedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+	if m.Error.Valid {
+		libraryImport.Error = m.Error.String
+	}
+	if m.CompletedAt.Valid {
+		libraryImport.CompletedAt = m.CompletedAt.Time
+	}
+	return libraryImport
+}
+
+func fromLibraryImportEntity(libraryImport *entity.LibraryImport) *libraryImportModel {
+	return &libraryImportModel{
+		ID:                libraryImport.ID,
+		UserID:            libraryImport.UserID,
+		Status:            string(libraryImport.Status),
+		Phase:             string(libraryImport.Phase),
+		SavedTracksOffset: libraryImport.SavedTracksOffset,
+		SavedTracksTotal:  libraryImport.SavedTracksTotal,
+		PlaylistsOffset:   libraryImport.PlaylistsOffset,
+		PlaylistsTotal:    libraryImport.PlaylistsTotal,
+		TracksImported:    libraryImport.TracksImported,
+		PlaylistsImported: libraryImport.PlaylistsImported,
+		Error: sql.NullString{
+			String: libraryImport.Error,
+			Valid:  libraryImport.Error != "",
+		},
+		StartedAt: libraryImport.StartedAt,
+		UpdatedAt: libraryImport.UpdatedAt,
+		CompletedAt: sql.NullTime{
+			Time:  libraryImport.CompletedAt,
+			Valid: !libraryImport.CompletedAt.IsZero(),
+		},
+	}
+}
+
+func (r *LibraryImportRepository) Save(ctx context.Context, libraryImport *entity.LibraryImport) error {
+	if libraryImport.ID == "" {
+		libraryImport.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO spotify_library_imports (
+			id, user_id, status, phase, saved_tracks_offset, saved_tracks_total,
+			playlists_offset, playlists_total, tracks_imported, playlists_imported,
+			error, started_at, updated_at, completed_at
+		) VALUES (
+			:id, :user_id, :status, :phase, :saved_tracks_offset, :saved_tracks_total,
+			:playlists_offset, :playlists_total, :tracks_imported, :playlists_imported,
+			:error, :started_at, :updated_at, :completed_at
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			phase = EXCLUDED.phase,
+			saved_tracks_offset = EXCLUDED.saved_tracks_offset,
+			saved_tracks_total = EXCLUDED.saved_tracks_total,
+			playlists_offset = EXCLUDED.playlists_offset,
+			playlists_total = EXCLUDED.playlists_total,
+			tracks_imported = EXCLUDED.tracks_imported,
+			playlists_imported = EXCLUDED.playlists_imported,
+			error = EXCLUDED.error,
+			updated_at = EXCLUDED.updated_at,
+			completed_at = EXCLUDED.completed_at
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, fromLibraryImportEntity(libraryImport))
+	if err != nil {
+		return fmt.Errorf("failed to save library import: %w", err)
+	}
+
+	return nil
+}
+
+func (r *LibraryImportRepository) GetLatestForUser(ctx context.Context, userID string) (*entity.LibraryImport, error) {
+	query := `
+		SELECT * FROM spotify_library_imports
+		WHERE user_id = $1
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+
+	var model libraryImportModel
+	err := r.db.GetContext(ctx, &model, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("library import not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get library import: %w", err)
+	}
+
+	return model.toEntity(), nil
+}
@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"spotify_recommender/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ListeningHistoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewListeningHistoryRepository(db *sqlx.DB) *ListeningHistoryRepository {
+	return &ListeningHistoryRepository{
+		db: db,
+	}
+}
+
+type playEventModel struct {
+	ID              string          `db:"id"`
+	UserID          string          `db:"user_id"`
+	Source          string          `db:"source"`
+	TrackID         sql.NullString  `db:"track_id"`
+	SpotifyArtistID sql.NullString  `db:"spotify_artist_id"`
+	ArtistName      sql.NullString  `db:"artist_name"`
+	Genres          json.RawMessage `db:"genres"`
+	TimeRange       sql.NullString  `db:"time_range"`
+	Rank            int             `db:"rank"`
+	PlayedAt        time.Time       `db:"played_at"`
+	CreatedAt       time.Time       `db:"created_at"`
+}
+
+type listeningSyncModel struct {
+	UserID                 string       `db:"user_id"`
+	RecentlyPlayedCursor   sql.NullTime `db:"recently_played_cursor"`
+	RecentlyPlayedSyncedAt sql.NullTime `db:"recently_played_synced_at"`
+	TopItemsSyncedAt       sql.NullTime `db:"top_items_synced_at"`
+}
+
+func (m *playEventModel) toEntity() (*entity.PlayEvent, error) {
+	var genres []string
+	if len(m.Genres) > 0 {
+		if err := json.Unmarshal(m.Genres, &genres); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal genres: %w", err)
+		}
+	}
+
+	return &entity.PlayEvent{
+		ID:              m.ID,
+		UserID:          m.UserID,
+		Source:          entity.PlayEventSource(m.Source),
+		TrackID:         m.TrackID.String,
+		SpotifyArtistID: m.SpotifyArtistID.String,
+		ArtistName:      m.ArtistName.String,
+		Genres:          genres,
+		TimeRange:       entity.TopTimeRange(m.TimeRange.String),
+		Rank:            m.Rank,
+		PlayedAt:        m.PlayedAt,
+		CreatedAt:       m.CreatedAt,
+	}, nil
+}
+
+func fromPlayEventEntity(event *entity.PlayEvent) (*playEventModel, error) {
+	genresJSON, err := json.Marshal(event.Genres)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal genres: %w", err)
+	}
+
+	return &playEventModel{
+		ID:              event.ID,
+		UserID:          event.UserID,
+		Source:          string(event.Source),
+		TrackID:         nullString(event.TrackID),
+		SpotifyArtistID: nullString(event.SpotifyArtistID),
+		ArtistName:      nullString(event.ArtistName),
+		Genres:          genresJSON,
+		TimeRange:       nullString(string(event.TimeRange)),
+		Rank:            event.Rank,
+		PlayedAt:        event.PlayedAt,
+		CreatedAt:       event.CreatedAt,
+	}, nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func nullTime(value time.Time) sql.NullTime {
+	return sql.NullTime{Time: value, Valid: !value.IsZero()}
+}
+
+const insertPlayEventQuery = `
+	INSERT INTO user_play_events (
+		id, user_id, source, track_id, spotify_artist_id, artist_name,
+		genres, time_range, rank, played_at, created_at
+	) VALUES (
+		:id, :user_id, :source, :track_id, :spotify_artist_id, :artist_name,
+		:genres, :time_range, :rank, :played_at, :created_at
+	)
+`
+
+func (r *ListeningHistoryRepository) SaveRecentPlays(ctx context.Context, events []*entity.PlayEvent) error {
+	query := insertPlayEventQuery + `
+		ON CONFLICT (user_id, track_id, played_at) WHERE source = 'recently_played'
+		DO NOTHING
+	`
+
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = uuid.New().String()
+		}
+
+		model, err := fromPlayEventEntity(event)
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+			return fmt.Errorf("failed to save play event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ListeningHistoryRepository) ReplaceTopItems(
+	ctx context.Context,
+	userID string,
+	source entity.PlayEventSource,
+	timeRange entity.TopTimeRange,
+	events []*entity.PlayEvent,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	deleteQuery := `
+		DELETE FROM user_play_events
+		WHERE user_id = $1 AND source = $2 AND time_range = $3
+	`
+	_, err = tx.ExecContext(ctx, deleteQuery, userID, string(source), string(timeRange))
+	if err != nil {
+		return fmt.Errorf("failed to delete top items: %w", err)
+	}
+
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = uuid.New().String()
+		}
+
+		var model *playEventModel
+		model, err = fromPlayEventEntity(event)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.NamedExecContext(ctx, insertPlayEventQuery, model)
+		if err != nil {
+			return fmt.Errorf("failed to save top item: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *ListeningHistoryRepository) GetRecentPlays(
+	ctx context.Context,
+	userID string,
+	since time.Time,
+	limit int,
+) ([]*entity.PlayEvent, error) {
+	query := `
+		SELECT * FROM user_play_events
+		WHERE user_id = $1 AND source = $2 AND played_at > $3
+		ORDER BY played_at DESC
+		LIMIT $4
+	`
+
+	return r.selectEvents(ctx, query, userID, string(entity.PlayEventRecentlyPlayed), since, limit)
+}
+
+func (r *ListeningHistoryRepository) GetTopItems(
+	ctx context.Context,
+	userID string,
+	source entity.PlayEventSource,
+	timeRange entity.TopTimeRange,
+) ([]*entity.PlayEvent, error) {
+	query := `
+		SELECT * FROM user_play_events
+		WHERE user_id = $1 AND source = $2 AND time_range = $3
+		ORDER BY rank
+	`
+
+	return r.selectEvents(ctx, query, userID, string(source), string(timeRange))
+}
+
+func (r *ListeningHistoryRepository) selectEvents(ctx context.Context, query string, args ...interface{}) ([]*entity.PlayEvent, error) {
+	var models []playEventModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get play events: %w", err)
+	}
+
+	events := make([]*entity.PlayEvent, 0, len(models))
+	for i := range models {
+		event, err := models[i].toEntity()
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (r *ListeningHistoryRepository) GetSyncState(ctx context.Context, userID string) (*entity.ListeningSync, error) {
+	query := `SELECT * FROM spotify_listening_syncs WHERE user_id = $1`
+
+	var model listeningSyncModel
+	err := r.db.GetContext(ctx, &model, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("listening sync state not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get listening sync state: %w", err)
+	}
+
+	return &entity.ListeningSync{
+		UserID:                 model.UserID,
+		RecentlyPlayedCursor:   model.RecentlyPlayedCursor.Time,
+		RecentlyPlayedSyncedAt: model.RecentlyPlayedSyncedAt.Time,
+		TopItemsSyncedAt:       model.TopItemsSyncedAt.Time,
+	}, nil
+}
+
+func (r *ListeningHistoryRepository) SaveSyncState(ctx context.Context, state *entity.ListeningSync) error {
+	query := `
+		INSERT INTO spotify_listening_syncs (
+			user_id, recently_played_cursor, recently_played_synced_at, top_items_synced_at
+		) VALUES (
+			:user_id, :recently_played_cursor, :recently_played_synced_at, :top_items_synced_at
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			recently_played_cursor = EXCLUDED.recently_played_cursor,
+			recently_played_synced_at = EXCLUDED.recently_played_synced_at,
+			top_items_synced_at = EXCLUDED.top_items_synced_at
+	`
+
+	model := &listeningSyncModel{
+		UserID:                 state.UserID,
+		RecentlyPlayedCursor:   nullTime(state.RecentlyPlayedCursor),
+		RecentlyPlayedSyncedAt: nullTime(state.RecentlyPlayedSyncedAt),
+		TopItemsSyncedAt:       nullTime(state.TopItemsSyncedAt),
+	}
+
+	_, err := r.db.NamedExecContext(ctx, query, model)
+	if err != nil {
+		return fmt.Errorf("failed to save listening sync state: %w", err)
+	}
+
+	return nil
+}
@@ -47,20 +47,28 @@ func fromPlaylistEntity(playlist *entity.Playlist) *playlistModel {
 		}
 	}
 
+	if playlist.SpotifyPlaylistID != "" {
+		model.SpotifyPlaylistID = sql.NullString{
+			String: playlist.SpotifyPlaylistID,
+			Valid:  true,
+		}
+	}
+
 	return model
 }
 
 type playlistModel struct {
-	ID          string         `db:"id"`
-	UserID      string         `db:"user_id"`
-	Name        string         `db:"name"`
-	Description string         `db:"description"`
-	Mood        string         `db:"mood"`
-	Weather     sql.NullString `db:"weather"`
-	TimeOfDay   sql.NullString `db:"time_of_day"`
-	IsPublic    bool           `db:"is_public"`
-	CreatedAt   time.Time      `db:"created_at"`
-	UpdatedAt   time.Time      `db:"updated_at"`
+	ID                string         `db:"id"`
+	UserID            string         `db:"user_id"`
+	Name              string         `db:"name"`
+	Description       string         `db:"description"`
+	Mood              string         `db:"mood"`
+	Weather           sql.NullString `db:"weather"`
+	TimeOfDay         sql.NullString `db:"time_of_day"`
+	IsPublic          bool           `db:"is_public"`
+	SpotifyPlaylistID sql.NullString `db:"spotify_playlist_id"`
+	CreatedAt         time.Time      `db:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at"`
 }
 
 func (m *playlistModel) toEntity() (*entity.Playlist, error) {
@@ -78,6 +86,9 @@ func (m *playlistModel) toEntity() (*entity.Playlist, error) {
 		playlist.TimeOfDay = valueObject.TimeOfDay(m.TimeOfDay.String)
 	}
 	playlist.IsPublic = m.IsPublic
+	if m.SpotifyPlaylistID.Valid {
+		playlist.SpotifyPlaylistID = m.SpotifyPlaylistID.String
+	}
 	playlist.CreatedAt = m.CreatedAt
 	playlist.UpdatedAt = m.UpdatedAt
 
@@ -113,6 +124,35 @@ func (r *PlaylistRepository) GetByID(ctx context.Context, id string) (*entity.Pl
 	return playlist, nil
 }
 
+func (r *PlaylistRepository) GetBySpotifyPlaylistID(ctx context.Context, userID, spotifyPlaylistID string) (*entity.Playlist, error) {
+	query := `
+		SELECT * FROM playlists
+		WHERE user_id = $1 AND spotify_playlist_id = $2
+	`
+
+	var model playlistModel
+	err := r.db.GetContext(ctx, &model, query, userID, spotifyPlaylistID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("playlist not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get playlist by Spotify ID: %w", err)
+	}
+
+	playlist, err := model.toEntity()
+	if err != nil {
+		return nil, err
+	}
+	tracks, err := r.getPlaylistTracksIDs(ctx, playlist.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+
+	playlist.Tracks = tracks
+
+	return playlist, nil
+}
+
 func (r *PlaylistRepository) getPlaylistTracksIDs(ctx context.Context, playlistID string) ([]string, error) {
 	query := `SELECT track_id FROM playlist_tracks
 WHERE playlist_id=$1
@@ -135,7 +175,7 @@ func (r *PlaylistRepository) Save(ctx context.Context, playlist *entity.Playlist
 		playlist.CreatedAt = now
 	}
 	if playlist.UpdatedAt.IsZero() {
-		playlist.CreatedAt = now
+		playlist.UpdatedAt = now
 	}
 	model := fromPlaylistEntity(playlist)
 	tx, err := r.db.BeginTxx(ctx, nil)
@@ -148,9 +188,9 @@ func (r *PlaylistRepository) Save(ctx context.Context, playlist *entity.Playlist
 		}
 	}()
 	query := `INSERT INTO playlists (	id, user_id, name, description, mood, weather, time_of_day,
-			is_public, created_at, updated_at) VALUES
+			is_public, spotify_playlist_id, created_at, updated_at) VALUES
 			(	:id, :user_id, :name, :description, :mood, :weather, :time_of_day,
-			:is_public, :created_at, :updated_at)`
+			:is_public, :spotify_playlist_id, :created_at, :updated_at)`
 
 	_, err = tx.NamedExecContext(ctx, query, model)
 	if err != nil {
@@ -161,7 +201,7 @@ func (r *PlaylistRepository) Save(ctx context.Context, playlist *entity.Playlist
 		insertQuery := ` INSERT INTO playlist_tracks (playlist_id, track_id, position)
 			VALUES ($1, $2, $3)`
 		for i, trackID := range playlist.Tracks {
-			_, err = tx.ExecContext(ctx, insertQuery, playlist.Name, trackID, i)
+			_, err = tx.ExecContext(ctx, insertQuery, playlist.ID, trackID, i)
 			if err != nil {
 				return fmt.Errorf("failed to add track to playlist: %w", err)
 			}
@@ -197,6 +237,7 @@ func (r *PlaylistRepository) Update(ctx context.Context, playlist *entity.Playli
 			weather = :weather,
 			time_of_day = :time_of_day,
 			is_public = :is_public,
+			spotify_playlist_id = :spotify_playlist_id,
 			updated_at = :updated_at
 		WHERE id = :id`
 
@@ -275,6 +316,28 @@ func (r *PlaylistRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (r *PlaylistRepository) SetSpotifyPlaylistID(ctx context.Context, playlistID, spotifyPlaylistID string) error {
+	query := `
+		UPDATE playlists SET spotify_playlist_id = $1
+		WHERE id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, spotifyPlaylistID, playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to set spotify playlist ID: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("playlist with ID %s not found", playlistID)
+	}
+
+	return nil
+}
+
 func (r *PlaylistRepository) GetUserPlaylists(ctx context.Context, userID string) ([]*entity.Playlist, error) {
 	query := `
 		SELECT * FROM playlists
@@ -312,14 +375,15 @@ func (r *PlaylistRepository) GetUserPlaylists(ctx context.Context, userID string
 
 	return playlists, nil
 }
-func (r *PlaylistRepository) FindByName(ctx context.Context, name string) ([]*entity.Playlist, error) {
+func (r *PlaylistRepository) FindByName(ctx context.Context, name string, limit, offset int) ([]*entity.Playlist, error) {
 	query := `
 		SELECT * FROM playlists
 		WHERE name ILIKE $1 AND is_public = TRUE
 		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryxContext(ctx, query, "%"+name+"%")
+	rows, err := r.db.QueryxContext(ctx, query, "%"+name+"%", limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find playlists by name: %w", err)
 	}
@@ -350,14 +414,15 @@ func (r *PlaylistRepository) FindByName(ctx context.Context, name string) ([]*en
 
 }
 
-func (r *PlaylistRepository) FindByMood(ctx context.Context, mood valueObject.Mood) ([]*entity.Playlist, error) {
+func (r *PlaylistRepository) FindByMood(ctx context.Context, mood valueObject.Mood, limit, offset int) ([]*entity.Playlist, error) {
 	query := `
 		SELECT * FROM playlists
 		WHERE mood = $1 AND is_public = TRUE
 		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryxContext(ctx, query, string(mood))
+	rows, err := r.db.QueryxContext(ctx, query, string(mood), limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find playlists by mood: %w", err)
 	}
@@ -548,6 +613,55 @@ func (r *PlaylistRepository) GetPlaylistTracks(ctx context.Context, playlistID s
 	return tracks, nil
 }
 
+// GetPlaylistsTracks returns the tracks of each of the playlists in order,
+// keyed by playlist ID, using one query for all of them.
+func (r *PlaylistRepository) GetPlaylistsTracks(ctx context.Context, playlistIDs []string) (map[string][]*entity.Track, error) {
+	result := make(map[string][]*entity.Track, len(playlistIDs))
+	if len(playlistIDs) == 0 {
+		return result, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT pt.playlist_id, t.* FROM tracks t
+		JOIN playlist_tracks pt ON t.id = pt.track_id
+		WHERE pt.playlist_id IN (?)
+		ORDER BY pt.playlist_id, pt.position
+	`, playlistIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build playlist tracks query: %w", err)
+	}
+
+	rows, err := r.db.QueryxContext(ctx, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model struct {
+			PlaylistID string `db:"playlist_id"`
+			trackModel
+		}
+
+		if err := rows.StructScan(&model); err != nil {
+			return nil, fmt.Errorf("failed to scan track model: %w", err)
+		}
+
+		track, err := model.ToEntity()
+		if err != nil {
+			continue
+		}
+
+		result[model.PlaylistID] = append(result[model.PlaylistID], track)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through tracks: %w", err)
+	}
+
+	return result, nil
+}
+
 func (r *PlaylistRepository) GetPublicPlaylists(ctx context.Context, limit, offset int) ([]*entity.Playlist, error) {
 	query := `
 		SELECT * FROM playlists
@@ -29,6 +29,8 @@ type recommendationModel struct {
 	Mood      string          `db:"mood"`
 	Weather   string          `db:"weather"`
 	TimeOfDay string          `db:"time_of_day"`
+	DayPart   string          `db:"day_part"`
+	DayType   string          `db:"day_type"`
 	TrackIDs  json.RawMessage `db:"track_ids"`
 	CreatedAt time.Time       `db:"created_at"`
 	ExpiresAt time.Time       `db:"expires_at"`
@@ -48,7 +50,11 @@ func (m *recommendationModel) toEntity() (*entity.Recommendation, error) {
 		m.UserID,
 		valueObject.Mood(m.Mood),
 		valueObject.Weather(m.Weather),
-		valueObject.TimeOfDay(m.TimeOfDay),
+		valueObject.TemporalContext{
+			TimeOfDay: valueObject.TimeOfDay(m.TimeOfDay),
+			DayPart:   valueObject.DayPart(m.DayPart),
+			DayType:   valueObject.DayType(m.DayType),
+		},
 		trackIDs,
 	)
 
@@ -71,6 +77,8 @@ func fromRecommendationEntity(rec *entity.Recommendation) (*recommendationModel,
 		Mood:      string(rec.Mood),
 		Weather:   string(rec.Weather),
 		TimeOfDay: string(rec.TimeOfDay),
+		DayPart:   string(rec.DayPart),
+		DayType:   string(rec.DayType),
 		TrackIDs:  trackIDsJSON,
 		CreatedAt: rec.CreatedAt,
 		ExpiresAt: rec.ExpiresAt,
@@ -119,7 +127,7 @@ func (r *RecommendationRepository) Save(ctx context.Context, recommendation *ent
 		recommendation.UserID,
 		recommendation.Mood,
 		recommendation.Weather,
-		recommendation.TimeOfDay,
+		recommendation.TemporalContext(),
 	)
 
 	if err == nil && existingRec != nil {
@@ -150,9 +158,9 @@ func (r *RecommendationRepository) Save(ctx context.Context, recommendation *ent
 
 	query := `
 		INSERT INTO recommendations (
-			id, user_id, mood, weather, time_of_day, track_ids, created_at, expires_at
+			id, user_id, mood, weather, time_of_day, day_part, day_type, track_ids, created_at, expires_at
 		) VALUES (
-			:id, :user_id, :mood, :weather, :time_of_day, :track_ids, :created_at, :expires_at
+			:id, :user_id, :mood, :weather, :time_of_day, :day_part, :day_type, :track_ids, :created_at, :expires_at
 		)
 	`
 
@@ -227,7 +235,7 @@ func (r *RecommendationRepository) FindByContext(
 	userID string,
 	mood valueObject.Mood,
 	weather valueObject.Weather,
-	timeOfDay valueObject.TimeOfDay,
+	temporal valueObject.TemporalContext,
 ) (*entity.Recommendation, error) {
 	query := `
 		SELECT * FROM recommendations
@@ -235,7 +243,9 @@ func (r *RecommendationRepository) FindByContext(
 		AND mood = $2
 		AND weather = $3
 		AND time_of_day = $4
-		AND expires_at > $5
+		AND day_part = $5
+		AND day_type = $6
+		AND expires_at > $7
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
@@ -248,7 +258,9 @@ func (r *RecommendationRepository) FindByContext(
 		userID,
 		string(mood),
 		string(weather),
-		string(timeOfDay),
+		string(temporal.TimeOfDay),
+		string(temporal.DayPart),
+		string(temporal.DayType),
 		time.Now(),
 	)
 
@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"spotify_recommender/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type SessionRepository struct {
+	db *sqlx.DB
+}
+
+func NewSessionRepository(db *sqlx.DB) *SessionRepository {
+	return &SessionRepository{
+		db: db,
+	}
+}
+
+type sessionModel struct {
+	ID        string       `db:"id"`
+	UserID    string       `db:"user_id"`
+	CreatedAt time.Time    `db:"created_at"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	RevokedAt sql.NullTime `db:"revoked_at"`
+}
+
+func (m *sessionModel) toEntity() *entity.Session {
+	session := &entity.Session{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		CreatedAt: m.CreatedAt,
+		ExpiresAt: m.ExpiresAt,
+	}
+	if m.RevokedAt.Valid {
+		session.RevokedAt = m.RevokedAt.Time
+	}
+	return session
+}
+
+type refreshTokenModel struct {
+	ID        string       `db:"id"`
+	SessionID string       `db:"session_id"`
+	UserID    string       `db:"user_id"`
+	TokenHash string       `db:"token_hash"`
+	CreatedAt time.Time    `db:"created_at"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at"`
+}
+
+func (m *refreshTokenModel) toEntity() *entity.RefreshToken {
+	token := &entity.RefreshToken{
+		ID:        m.ID,
+		SessionID: m.SessionID,
+		UserID:    m.UserID,
+		TokenHash: m.TokenHash,
+		CreatedAt: m.CreatedAt,
+		ExpiresAt: m.ExpiresAt,
+	}
+	if m.UsedAt.Valid {
+		token.UsedAt = m.UsedAt.Time
+	}
+	return token
+}
+
+func (r *SessionRepository) CreateSession(ctx context.Context, session *entity.Session) error {
+	if session.ID == "" {
+		session.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO auth_sessions (id, user_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, session.ID, session.UserID, session.CreatedAt, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) GetSession(ctx context.Context, id string) (*entity.Session, error) {
+	query := `SELECT * FROM auth_sessions WHERE id = $1`
+
+	var model sessionModel
+	err := r.db.GetContext(ctx, &model, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("session not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get session by ID: %w", err)
+	}
+
+	return model.toEntity(), nil
+}
+
+func (r *SessionRepository) RevokeSession(ctx context.Context, id string) error {
+	query := `
+		UPDATE auth_sessions SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	query := `
+		UPDATE auth_sessions SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) SaveRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	if token.ID == "" {
+		token.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO refresh_tokens (id, session_id, user_id, token_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID, token.SessionID, token.UserID, token.TokenHash, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	query := `SELECT * FROM refresh_tokens WHERE token_hash = $1`
+
+	var model refreshTokenModel
+	err := r.db.GetContext(ctx, &model, query, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("refresh token not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return model.toEntity(), nil
+}
+
+func (r *SessionRepository) MarkRefreshTokenUsed(ctx context.Context, id string) (bool, error) {
+	query := `
+		UPDATE refresh_tokens SET used_at = $1
+		WHERE id = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark refresh token as used: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *SessionRepository) DeleteExpired(ctx context.Context) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE expires_at <= $1`, now)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM auth_sessions WHERE expires_at <= $1`, now)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
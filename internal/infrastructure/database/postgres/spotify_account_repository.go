@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/infrastructure/crypto"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type SpotifyAccountRepository struct {
+	db     *sqlx.DB
+	cipher *crypto.Cipher
+}
+
+func NewSpotifyAccountRepository(db *sqlx.DB, cipher *crypto.Cipher) *SpotifyAccountRepository {
+	return &SpotifyAccountRepository{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+type spotifyTokenModel struct {
+	UserID                string    `db:"user_id"`
+	SpotifyUserID         string    `db:"spotify_user_id"`
+	AccessTokenEncrypted  string    `db:"access_token_encrypted"`
+	RefreshTokenEncrypted string    `db:"refresh_token_encrypted"`
+	TokenType             string    `db:"token_type"`
+	Scope                 string    `db:"scope"`
+	ExpiresAt             time.Time `db:"expires_at"`
+	CreatedAt             time.Time `db:"created_at"`
+	UpdatedAt             time.Time `db:"updated_at"`
+}
+
+type oauthStateModel struct {
+	State     string    `db:"state"`
+	UserID    string    `db:"user_id"`
+	CreatedAt time.Time `db:"created_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+// toEntity decrypts the model's tokens. They are encrypted with the user ID as
+// associated data, so tokens copied into another user's row do not decrypt.
+func (r *SpotifyAccountRepository) toEntity(m *spotifyTokenModel) (*entity.SpotifyToken, error) {
+	accessToken, err := r.cipher.Decrypt(m.AccessTokenEncrypted, []byte(m.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt access token: %w", err)
+	}
+
+	refreshToken, err := r.cipher.Decrypt(m.RefreshTokenEncrypted, []byte(m.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt refresh token: %w", err)
+	}
+
+	return &entity.SpotifyToken{
+		UserID:        m.UserID,
+		SpotifyUserID: m.SpotifyUserID,
+		AccessToken:   accessToken,
+		RefreshToken:  refreshToken,
+		TokenType:     m.TokenType,
+		Scope:         m.Scope,
+		Expiry:        m.ExpiresAt,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}, nil
+}
+
+func (r *SpotifyAccountRepository) fromEntity(token *entity.SpotifyToken) (*spotifyTokenModel, error) {
+	accessToken, err := r.cipher.Encrypt(token.AccessToken, []byte(token.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt access token: %w", err)
+	}
+
+	refreshToken, err := r.cipher.Encrypt(token.RefreshToken, []byte(token.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt refresh token: %w", err)
+	}
+
+	return &spotifyTokenModel{
+		UserID:                token.UserID,
+		SpotifyUserID:         token.SpotifyUserID,
+		AccessTokenEncrypted:  accessToken,
+		RefreshTokenEncrypted: refreshToken,
+		TokenType:             token.TokenType,
+		Scope:                 token.Scope,
+		ExpiresAt:             token.Expiry,
+		CreatedAt:             token.CreatedAt,
+		UpdatedAt:             token.UpdatedAt,
+	}, nil
+}
+
+func (r *SpotifyAccountRepository) GetToken(ctx context.Context, userID string) (*entity.SpotifyToken, error) {
+	query := `SELECT * FROM spotify_tokens WHERE user_id = $1`
+
+	var model spotifyTokenModel
+	err := r.db.GetContext(ctx, &model, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("spotify token not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get spotify token: %w", err)
+	}
+
+	return r.toEntity(&model)
+}
+
+func (r *SpotifyAccountRepository) SaveToken(ctx context.Context, token *entity.SpotifyToken) error {
+	now := time.Now()
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = now
+	}
+	token.UpdatedAt = now
+
+	model, err := r.fromEntity(token)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO spotify_tokens (
+			user_id, spotify_user_id, access_token_encrypted, refresh_token_encrypted,
+			token_type, scope, expires_at, created_at, updated_at
+		) VALUES (
+			:user_id, :spotify_user_id, :access_token_encrypted, :refresh_token_encrypted,
+			:token_type, :scope, :expires_at, :created_at, :updated_at
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			spotify_user_id = EXCLUDED.spotify_user_id,
+			access_token_encrypted = EXCLUDED.access_token_encrypted,
+			refresh_token_encrypted = EXCLUDED.refresh_token_encrypted,
+			token_type = EXCLUDED.token_type,
+			scope = EXCLUDED.scope,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.db.NamedExecContext(ctx, query, model)
+	if err != nil {
+		return fmt.Errorf("failed to save spotify token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SpotifyAccountRepository) DeleteToken(ctx context.Context, userID string) error {
+	query := `DELETE FROM spotify_tokens WHERE user_id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete spotify token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SpotifyAccountRepository) ListLinkedUserIDs(ctx context.Context) ([]string, error) {
+	query := `SELECT user_id FROM spotify_tokens ORDER BY user_id`
+
+	var userIDs []string
+	if err := r.db.SelectContext(ctx, &userIDs, query); err != nil {
+		return nil, fmt.Errorf("failed to list linked spotify users: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+func (r *SpotifyAccountRepository) SaveState(ctx context.Context, state *entity.OAuthState) error {
+	query := `
+		INSERT INTO spotify_oauth_states (state, user_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, state.State, state.UserID, state.CreatedAt, state.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to save oauth state: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SpotifyAccountRepository) ConsumeState(ctx context.Context, state string) (*entity.OAuthState, error) {
+	query := `
+		DELETE FROM spotify_oauth_states
+		WHERE state = $1
+		RETURNING state, user_id, created_at, expires_at
+	`
+
+	var model oauthStateModel
+	err := r.db.GetContext(ctx, &model, query, state)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("oauth state not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to consume oauth state: %w", err)
+	}
+
+	return &entity.OAuthState{
+		State:     model.State,
+		UserID:    model.UserID,
+		CreatedAt: model.CreatedAt,
+		ExpiresAt: model.ExpiresAt,
+	}, nil
+}
@@ -25,18 +25,25 @@ func NewTrackRepository(db *sqlx.DB) *TrackRepository {
 }
 
 type trackModel struct {
-	ID            string          `db:"id"`
-	SpotifyID     string          `db:"spotify_id"`
-	Name          string          `db:"name"`
-	Artist        string          `db:"artist"`
-	Album         string          `db:"album"`
-	ReleaseDate   time.Time       `db:"release_date"`
-	Popularity    int             `db:"popularity"`
-	AudioFeatures json.RawMessage `db:"audio_features"`
-	PreviewURL    string          `db:"preview_url"`
-	ImageURL      string          `db:"image_url"`
-	CreatedAt     time.Time       `db:"created_at"`
-	UpdatedAt     time.Time       `db:"updated_at"`
+	ID                   string          `db:"id"`
+	SpotifyID            string          `db:"spotify_id"`
+	Name                 string          `db:"name"`
+	Artist               string          `db:"artist"`
+	Artists              json.RawMessage `db:"artists"`
+	Genres               json.RawMessage `db:"genres"`
+	Album                string          `db:"album"`
+	ReleaseDate          time.Time       `db:"release_date"`
+	ReleaseDatePrecision string          `db:"release_date_precision"`
+	ISRC                 string          `db:"isrc"`
+	Explicit             bool            `db:"explicit"`
+	DurationMS           int             `db:"duration_ms"`
+	Popularity           int             `db:"popularity"`
+	AudioFeatures        json.RawMessage `db:"audio_features"`
+	PreviewURL           string          `db:"preview_url"`
+	ExternalURL          string          `db:"external_url"`
+	ImageURL             string          `db:"image_url"`
+	CreatedAt            time.Time       `db:"created_at"`
+	UpdatedAt            time.Time       `db:"updated_at"`
 }
 
 func (m *trackModel) ToEntity() (*entity.Track, error) {
@@ -57,7 +64,23 @@ func (m *trackModel) ToEntity() (*entity.Track, error) {
 		m.ImageURL,
 	)
 
+	if len(m.Artists) > 0 {
+		if err := json.Unmarshal(m.Artists, &track.Artists); err != nil {
+			return nil, err
+		}
+	}
+	if len(m.Genres) > 0 {
+		if err := json.Unmarshal(m.Genres, &track.Genres); err != nil {
+			return nil, err
+		}
+	}
+
 	track.ID = m.ID
+	track.ReleaseDatePrecision = entity.ReleaseDatePrecision(m.ReleaseDatePrecision)
+	track.ISRC = m.ISRC
+	track.Explicit = m.Explicit
+	track.DurationMS = m.DurationMS
+	track.ExternalURL = m.ExternalURL
 	track.CreatedAt = m.CreatedAt
 	track.UpdatedAt = m.UpdatedAt
 
@@ -69,20 +92,43 @@ func fromTrackEntity(track *entity.Track) (*trackModel, error) {
 	if err != nil {
 		return nil, err
 	}
+	artists := track.Artists
+	if artists == nil {
+		artists = []entity.TrackArtist{}
+	}
+	artistsJSON, err := json.Marshal(artists)
+	if err != nil {
+		return nil, err
+	}
+	genres := track.Genres
+	if genres == nil {
+		genres = []string{}
+	}
+	genresJSON, err := json.Marshal(genres)
+	if err != nil {
+		return nil, err
+	}
 
 	return &trackModel{
-		ID:            track.ID,
-		SpotifyID:     track.SpotifyID,
-		Name:          track.Name,
-		Artist:        track.Artist,
-		Album:         track.Album,
-		ReleaseDate:   track.ReleaseDate,
-		Popularity:    track.Popularity,
-		AudioFeatures: audioFeaturesJSON,
-		PreviewURL:    track.PreviewURL,
-		ImageURL:      track.ImageURL,
-		CreatedAt:     track.CreatedAt,
-		UpdatedAt:     track.UpdatedAt,
+		ID:                   track.ID,
+		SpotifyID:            track.SpotifyID,
+		Name:                 track.Name,
+		Artist:               track.Artist,
+		Artists:              artistsJSON,
+		Genres:               genresJSON,
+		Album:                track.Album,
+		ReleaseDate:          track.ReleaseDate,
+		ReleaseDatePrecision: string(track.ReleaseDatePrecision),
+		ISRC:                 track.ISRC,
+		Explicit:             track.Explicit,
+		DurationMS:           track.DurationMS,
+		Popularity:           track.Popularity,
+		AudioFeatures:        audioFeaturesJSON,
+		PreviewURL:           track.PreviewURL,
+		ExternalURL:          track.ExternalURL,
+		ImageURL:             track.ImageURL,
+		CreatedAt:            track.CreatedAt,
+		UpdatedAt:            track.UpdatedAt,
 	}, nil
 }
 
@@ -100,8 +146,36 @@ func (r *TrackRepository) GetByID(ctx context.Context, id string) (*entity.Track
 	return model.ToEntity()
 }
 
+// GetByIDs returns the tracks with the given IDs, in no particular order.
+// IDs without a track are skipped.
+func (r *TrackRepository) GetByIDs(ctx context.Context, ids []string) ([]*entity.Track, error) {
+	if len(ids) == 0 {
+		return []*entity.Track{}, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM tracks WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tracks query: %w", err)
+	}
+
+	var models []trackModel
+	if err := r.db.SelectContext(ctx, &models, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get tracks: %w", err)
+	}
+
+	tracks := make([]*entity.Track, 0, len(models))
+	for _, model := range models {
+		track, err := model.ToEntity()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode track %s: %w", model.ID, err)
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, nil
+}
+
 func (r *TrackRepository) GetBySpotifyID(ctx context.Context, spotifyID string) (*entity.Track, error) {
-	query := `SELECT *FROM tracks WHERE spotify_id  =%1`
+	query := `SELECT *FROM tracks WHERE spotify_id = $1`
 
 	var model trackModel
 	err := r.db.GetContext(ctx, &model, query, spotifyID)
@@ -122,15 +196,70 @@ func (r *TrackRepository) Save(ctx context.Context, track *entity.Track) error {
 	if err != nil {
 		return err
 	}
-	query := `INSERT INTO tracks (id, spotify_id, name, artist, album, release_date, popularity,
-			audio_features, preview_url, image_url, created_at, updated_at)
-			values (:id, :spotify_id, :name, :artist, :album, :release_date, :popularity,
-			:audio_features, :preview_url, :image_url, :created_at, :updated_at)`
+	query := `INSERT INTO tracks (id, spotify_id, name, artist, artists, genres, album, release_date,
+			release_date_precision, isrc, explicit, duration_ms, popularity,
+			audio_features, preview_url, external_url, image_url, created_at, updated_at)
+			values (:id, :spotify_id, :name, :artist, :artists, :genres, :album, :release_date,
+			:release_date_precision, :isrc, :explicit, :duration_ms, :popularity,
+			:audio_features, :preview_url, :external_url, :image_url, :created_at, :updated_at)`
 
 	_, err = r.db.NamedExecContext(ctx, query, model)
 	return err
 }
 
+// Upsert inserts the track or, if a track with the same Spotify ID exists,
+// refreshes its metadata. track.ID and CreatedAt are set to the stored values.
+func (r *TrackRepository) Upsert(ctx context.Context, track *entity.Track) error {
+	if track.ID == "" {
+		track.ID = uuid.New().String()
+	}
+	model, err := fromTrackEntity(track)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO tracks (id, spotify_id, name, artist, artists, genres, album, release_date,
+			release_date_precision, isrc, explicit, duration_ms, popularity,
+			audio_features, preview_url, external_url, image_url, created_at, updated_at)
+			values (:id, :spotify_id, :name, :artist, :artists, :genres, :album, :release_date,
+			:release_date_precision, :isrc, :explicit, :duration_ms, :popularity,
+			:audio_features, :preview_url, :external_url, :image_url, :created_at, :updated_at)
+			ON CONFLICT (spotify_id) DO UPDATE SET
+				name = EXCLUDED.name,
+				artist = EXCLUDED.artist,
+				artists = EXCLUDED.artists,
+				genres = EXCLUDED.genres,
+				album = EXCLUDED.album,
+				release_date = EXCLUDED.release_date,
+				release_date_precision = EXCLUDED.release_date_precision,
+				isrc = EXCLUDED.isrc,
+				explicit = EXCLUDED.explicit,
+				duration_ms = EXCLUDED.duration_ms,
+				popularity = EXCLUDED.popularity,
+				audio_features = EXCLUDED.audio_features,
+				preview_url = EXCLUDED.preview_url,
+				external_url = EXCLUDED.external_url,
+				image_url = EXCLUDED.image_url,
+				updated_at = EXCLUDED.updated_at
+			RETURNING id, created_at`
+
+	query, args, err := r.db.BindNamed(query, model)
+	if err != nil {
+		return fmt.Errorf("failed to bind track upsert: %w", err)
+	}
+
+	var stored struct {
+		ID        string    `db:"id"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	if err := r.db.GetContext(ctx, &stored, query, args...); err != nil {
+		return fmt.Errorf("failed to upsert track: %w", err)
+	}
+
+	track.ID = stored.ID
+	track.CreatedAt = stored.CreatedAt
+	return nil
+}
+
 func (r *TrackRepository) Update(ctx context.Context, track *entity.Track) error {
 	track.UpdatedAt = time.Now()
 
@@ -144,11 +273,18 @@ func (r *TrackRepository) Update(ctx context.Context, track *entity.Track) error
 			spotify_id = :spotify_id,
 			name = :name,
 			artist = :artist,
+			artists = :artists,
+			genres = :genres,
 			album = :album,
 			release_date = :release_date,
+			release_date_precision = :release_date_precision,
+			isrc = :isrc,
+			explicit = :explicit,
+			duration_ms = :duration_ms,
 			popularity = :popularity,
 			audio_features = :audio_features,
 			preview_url = :preview_url,
+			external_url = :external_url,
 			image_url = :image_url,
 			updated_at = :updated_at
 		WHERE id = :id`
@@ -170,7 +306,7 @@ func (r *TrackRepository) Update(ctx context.Context, track *entity.Track) error
 	return nil
 }
 
-func (r *p) Delete(ctx context.Context, id string) error {
+func (r *TrackRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM tracks WHERE id=$1`
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
@@ -190,6 +326,10 @@ func (r *p) Delete(ctx context.Context, id string) error {
 func (r *TrackRepository) FindByArtist(ctx context.Context, artist string) ([]*entity.Track, error) {
 	query := `SELECT * FROM tracks
 			WHERE artist ILIKE $1
+			OR EXISTS (
+				SELECT 1 FROM jsonb_array_elements(artists) AS credited
+				WHERE credited->>'name' ILIKE $1
+			)
 			ORDER BY popularity DESC`
 
 	var models []trackModel
@@ -306,6 +446,33 @@ func (r *TrackRepository) FindByWeather(ctx context.Context, weather valueObject
 			LIMIT $1
 		`
 		args = []interface{}{limit}
+	case valueObject.WeatherWindy:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'energy' > '0.6'
+			AND audio_features->>'acousticness' < '0.4'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case valueObject.WeatherHot:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'energy' > '0.5'
+			AND audio_features->>'danceability' > '0.6'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case valueObject.WeatherCold:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'energy' < '0.6'
+			AND audio_features->>'acousticness' > '0.4'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
 	default:
 		query = `
 			SELECT * FROM tracks
@@ -317,7 +484,7 @@ func (r *TrackRepository) FindByWeather(ctx context.Context, weather valueObject
 
 	var models []*trackModel
 
-	err := r.db.SelectContext(ctx, models, query, args...)
+	err := r.db.SelectContext(ctx, &models, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -334,7 +501,7 @@ func (r *TrackRepository) FindByWeather(ctx context.Context, weather valueObject
 	return tracks, err
 }
 
-func (r *TrackRepository) FindByTimeOfDay(ctx context.Context, timeOfDay valueobject.TimeOfDay, limit int) ([]*entity.Track, error) {
+func (r *TrackRepository) FindByTimeOfDay(ctx context.Context, timeOfDay valueObject.TimeOfDay, limit int) ([]*entity.Track, error) {
 
 	var query string
 	var args []interface{}
@@ -377,11 +544,185 @@ func (r *TrackRepository) FindByTimeOfDay(ctx context.Context, timeOfDay valueob
 	return tracks, nil
 }
 
+func (r *TrackRepository) FindByDayPart(ctx context.Context, part valueObject.DayPart, limit int) ([]*entity.Track, error) {
+	var query string
+	var args []interface{}
+
+	switch part {
+	case valueObject.DayPartDawn:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'energy' < '0.5'
+			AND audio_features->>'acousticness' > '0.4'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case valueObject.DayPartMorningCommute:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'valence' > '0.5'
+			AND audio_features->>'energy' > '0.5'
+			AND audio_features->>'energy' < '0.85'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case valueObject.DayPartWorkHours:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'energy' > '0.3'
+			AND audio_features->>'energy' < '0.7'
+			AND audio_features->>'speechiness' < '0.2'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case valueObject.DayPartEveningCommute:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'valence' > '0.4'
+			AND audio_features->>'energy' > '0.5'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case valueObject.DayPartMorning:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'valence' > '0.5'
+			AND audio_features->>'energy' < '0.6'
+			AND audio_features->>'acousticness' > '0.3'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case valueObject.DayPartAfternoon:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'valence' > '0.5'
+			AND audio_features->>'danceability' > '0.5'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case valueObject.DayPartEvening:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'energy' > '0.3'
+			AND audio_features->>'energy' < '0.8'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case valueObject.DayPartLateNight:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'energy' < '0.4'
+			AND audio_features->>'acousticness' > '0.5'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	default:
+		query = `
+			SELECT * FROM tracks
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	}
+
+	var models []trackModel
+	err := r.db.SelectContext(ctx, &models, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]*entity.Track, 0, len(models))
+	for _, model := range models {
+		track, err := model.ToEntity()
+		if err != nil {
+			continue
+		}
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
+
+// FindByDayType finds tracks for the day part on a day of the given type,
+// as AudioFeatures.MatchesDayType judges them.
+func (r *TrackRepository) FindByDayType(
+	ctx context.Context,
+	dayType valueObject.DayType,
+	part valueObject.DayPart,
+	limit int,
+) ([]*entity.Track, error) {
+	var query string
+	var args []interface{}
+
+	switch {
+	case dayType.IsRestDay() && part.IsNight():
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'danceability' > '0.7'
+			AND audio_features->>'energy' > '0.7'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case part.IsNight():
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'energy' < '0.6'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	case dayType.IsRestDay():
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'valence' > '0.6'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	default:
+		query = `
+			SELECT * FROM tracks
+			WHERE audio_features->>'speechiness' < '0.33'
+			AND audio_features->>'energy' > '0.4'
+			AND audio_features->>'energy' < '0.8'
+			ORDER BY popularity DESC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
+	}
+
+	var models []trackModel
+	err := r.db.SelectContext(ctx, &models, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]*entity.Track, 0, len(models))
+	for _, model := range models {
+		track, err := model.ToEntity()
+		if err != nil {
+			continue
+		}
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
+
 func (r *TrackRepository) FindByMoodWeatherTime(
 	ctx context.Context,
 	mood valueObject.Mood,
 	weather valueObject.Weather,
-	timeOfDay valueObject.TimeOfDay,
+	temporal valueObject.TemporalContext,
 	limit int,
 ) ([]*entity.Track, error) {
 
@@ -395,7 +736,17 @@ func (r *TrackRepository) FindByMoodWeatherTime(
 		return nil, err
 	}
 
-	timeTracks, err := r.FindByTimeOfDay(ctx, timeOfDay, limit*2)
+	timeTracks, err := r.FindByTimeOfDay(ctx, temporal.TimeOfDay, limit*2)
+	if err != nil {
+		return nil, err
+	}
+
+	dayPartTracks, err := r.FindByDayPart(ctx, temporal.DayPart, limit*2)
+	if err != nil {
+		return nil, err
+	}
+
+	dayTypeTracks, err := r.FindByDayType(ctx, temporal.DayType, temporal.DayPart, limit*2)
 	if err != nil {
 		return nil, err
 	}
@@ -404,12 +755,12 @@ func (r *TrackRepository) FindByMoodWeatherTime(
 	trackMap := make(map[string]*entity.Track)
 
 	for _, track := range moodTracks {
-		trackScores[track.ID] += 3
+		trackScores[track.ID] += 6
 		trackMap[track.ID] = track
 	}
 
 	for _, track := range weatherTracks {
-		trackScores[track.ID] += 2
+		trackScores[track.ID] += 4
 		trackMap[track.ID] = track
 	}
 
@@ -418,6 +769,16 @@ func (r *TrackRepository) FindByMoodWeatherTime(
 		trackMap[track.ID] = track
 	}
 
+	for _, track := range dayPartTracks {
+		trackScores[track.ID] += 1
+		trackMap[track.ID] = track
+	}
+
+	for _, track := range dayTypeTracks {
+		trackScores[track.ID] += 1
+		trackMap[track.ID] = track
+	}
+
 	type trackScore struct {
 		track *entity.Track
 		score int
@@ -31,6 +31,7 @@ type userModel struct {
 	PasswordHash string          `db:"password_hash"`
 	Name         string          `db:"name"`
 	SpotifyID    sql.NullString  `db:"spotify_id"`
+	Timezone     string          `db:"timezone"`
 	Preferences  json.RawMessage `db:"preferences"`
 	LastLoginAt  time.Time       `db:"last_login_at"`
 	CreatedAt    time.Time       `db:"created_at"`
@@ -64,6 +65,7 @@ func (m *userModel) toEntity() (*entity.User, error) {
 		user.SpotifyID = m.SpotifyID.String
 	}
 
+	user.Timezone = m.Timezone
 	user.Preferences = preferences
 	user.LastLoginAt = m.LastLoginAt
 	user.CreatedAt = m.CreatedAt
@@ -83,6 +85,7 @@ func fromUserEntity(user *entity.User) (*userModel, error) {
 		Email:        user.Email,
 		PasswordHash: user.PasswordHash,
 		Name:         user.Name,
+		Timezone:     user.Timezone,
 		Preferences:  preferencesJSON,
 		LastLoginAt:  user.LastLoginAt,
 		CreatedAt:    user.CreatedAt,
@@ -181,10 +184,10 @@ func (r *UserRepository) Save(ctx context.Context, user *entity.User) error {
 
 	query := `
 		INSERT INTO users (
-			id, email, password_hash, name, spotify_id, preferences,
+			id, email, password_hash, name, spotify_id, timezone, preferences,
 			last_login_at, created_at, updated_at
 		) VALUES (
-			:id, :email, :password_hash, :name, :spotify_id, :preferences,
+			:id, :email, :password_hash, :name, :spotify_id, :timezone, :preferences,
 			:last_login_at, :created_at, :updated_at
 		)
 	`
@@ -211,6 +214,7 @@ func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
 			password_hash = :password_hash,
 			name = :name,
 			spotify_id = :spotify_id,
+			timezone = :timezone,
 			preferences = :preferences,
 			last_login_at = :last_login_at,
 			updated_at = :updated_at
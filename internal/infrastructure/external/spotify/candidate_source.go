@@ -0,0 +1,23 @@
+package spotify
+
+import (
+	"context"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/service"
+)
+
+// CandidateSource implements service.CandidateSource with Spotify's
+// recommendations, seeded from the request and tuned to its mood.
+type CandidateSource struct {
+	client *Client
+}
+
+func NewCandidateSource(client *Client) *CandidateSource {
+	return &CandidateSource{
+		client: client,
+	}
+}
+
+func (s *CandidateSource) Candidates(ctx context.Context, request service.CandidateRequest) ([]*entity.Track, error) {
+	return s.client.GetRecommendationsBySeeds(ctx, request.Seeds, request.Mood, request.Limit)
+}
@@ -1,6 +1,7 @@
 package spotify
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -10,50 +11,102 @@ import (
 	"net/http"
 	"net/url"
 	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
 	"spotify_recommender/internal/domain/valueObject"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 )
 
 const (
-	tokenURL          = "https://accounts.spotify.com/api/token"
-	apiURL            = "https://api.spotify.com/v1"
-	recommendationURL = apiURL + "/recommendations"
-	trackURL          = apiURL + "/tracks"
-	audioFeaturesURL  = apiURL + "/audio-features"
-	searchURL         = apiURL + "/search"
+	DefaultAccountsURL = "https://accounts.spotify.com"
+	DefaultAPIURL      = "https://api.spotify.com/v1"
+
+	tokenPath          = "/api/token"
+	authorizePath      = "/authorize"
+	recommendationPath = "/recommendations"
+	trackPath          = "/tracks"
+	audioFeaturesPath  = "/audio-features"
+	artistsPath        = "/artists"
+	searchPath         = "/search"
+	mePath             = "/me"
+	userPath           = "/users"
+	playlistsPath      = "/playlists"
+	recentlyPlayedPath = "/player/recently-played"
+	topTracksPath      = "/top/tracks"
+	topArtistsPath     = "/top/artists"
+	playerPath         = "/player"
+	devicesPath        = "/player/devices"
+	playPath           = "/player/play"
+	queuePath          = "/player/queue"
+
+	// Maximum number of IDs accepted by the multi-ID catalog endpoints.
+	maxTracksPerRequest         = 50
+	maxAudioFeaturesPerRequest  = 100
+	maxArtistsPerRequest        = 50
+	maxPlaylistTracksPerRequest = 100
 )
 
 type Config struct {
-	ClientID     string
-	ClientSecret string
-	RedirectURI  string
+	ClientID       string
+	ClientSecret   string
+	RedirectURI    string
+	AccountsURL    string
+	APIURL         string
+	Retry          RetryConfig
+	CircuitBreaker CircuitBreakerConfig
 }
 
+// Client talks to the Spotify Web API. Catalog endpoints are authorized with
+// an app-level client-credentials token; user endpoints go through the
+// client's TokenSource, set with ForUser or WithTokenSource.
 type Client struct {
-	config       Config
-	httpClient   *http.Client
-	accessToken  string
-	tokenExpiry  time.Time
-	refreshToken string
-	mutex        sync.Mutex
+	config         Config
+	httpClient     *http.Client
+	tokenSource    TokenSource
+	appTokenSource TokenSource
+	breaker        *circuitBreaker
 }
 
 func NewClient(config Config) *Client {
-	return &Client{
+	if config.AccountsURL == "" {
+		config.AccountsURL = DefaultAccountsURL
+	}
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+	if config.Retry.MaxAttempts <= 0 {
+		config.Retry = DefaultRetryConfig()
+	}
+	if config.CircuitBreaker.FailureThreshold <= 0 {
+		config.CircuitBreaker = DefaultCircuitBreakerConfig()
+	}
+
+	client := &Client{
 		config: config,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		breaker: newCircuitBreaker(config.CircuitBreaker),
 	}
+	client.appTokenSource = NewClientCredentialsTokenSource(client)
+
+	return client
 }
 
-func (c *Client) SetRefreshToken(refreshToken string) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+func (c *Client) WithTokenSource(tokenSource TokenSource) *Client {
+	return &Client{
+		config:         c.config,
+		httpClient:     c.httpClient,
+		tokenSource:    tokenSource,
+		appTokenSource: c.appTokenSource,
+		breaker:        c.breaker,
+	}
+}
 
-	c.refreshToken = refreshToken
+// ForUser returns a client that uses the stored, auto-refreshed credentials of userID.
+func (c *Client) ForUser(accountRepo repository.SpotifyAccountRepository, userID string) *Client {
+	return c.WithTokenSource(NewUserTokenSource(c, accountRepo, userID))
 }
 
 func (c *Client) GetAuthURL(state string, scopes []string) string {
@@ -64,10 +117,10 @@ func (c *Client) GetAuthURL(state string, scopes []string) string {
 	params.Add("state", state)
 	params.Add("scope", strings.Join(scopes, " "))
 
-	return "https://accounts.spotify.com/authorize?" + params.Encode()
+	return c.config.AccountsURL + authorizePath + "?" + params.Encode()
 }
 
-func (c *Client) ExchangeCodeForToken(ctx context.Context, code string) error {
+func (c *Client) ExchangeCodeForToken(ctx context.Context, code string) (*entity.SpotifyToken, error) {
 	data := url.Values{}
 	data.Set("grant_type", "authorization_code")
 	data.Set("code", code)
@@ -76,25 +129,62 @@ func (c *Client) ExchangeCodeForToken(ctx context.Context, code string) error {
 	return c.requestToken(ctx, data)
 }
 
-func (c *Client) refreshAccessToken(ctx context.Context) error {
-	if c.refreshToken == "" {
-		return errors.New("refresh token is not set")
+// GetTokenOwnerID returns the Spotify user ID the token was issued to.
+func (c *Client) GetTokenOwnerID(ctx context.Context, token *entity.SpotifyToken) (string, error) {
+	profile, err := c.WithTokenSource(StaticTokenSource(token)).GetCurrentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+	return profile.ID, nil
+}
+
+func (c *Client) GetCurrentUser(ctx context.Context) (*UserProfile, error) {
+	var profile UserProfile
+	if err := c.makeRequest(ctx, "GET", c.apiURL(mePath), nil, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (c *Client) refreshUserToken(ctx context.Context, refreshToken string) (*entity.SpotifyToken, error) {
+	if refreshToken == "" {
+		return nil, errors.New("refresh token is not set")
 	}
 
 	data := url.Values{}
 	data.Set("grant_type", "refresh_token")
-	data.Set("refresh_token", c.refreshToken)
+	data.Set("refresh_token", refreshToken)
 
-	return c.requestToken(ctx, data)
+	token, err := c.requestToken(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Spotify only returns a new refresh token when it rotates it.
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+
+	return token, nil
 }
 
-func (c *Client) requestToken(ctx context.Context, data url.Values) error {
+func (c *Client) requestClientCredentialsToken(ctx context.Context) (*entity.SpotifyToken, error) {
+	if c.config.ClientID == "" || c.config.ClientSecret == "" {
+		return nil, ErrNoTokenSource
+	}
 
+	data := url.Values{}
+	data.Set("grant_type", "client_credentials")
+
+	return c.requestToken(ctx, data)
+}
+
+func (c *Client) requestToken(ctx context.Context, data url.Values) (*entity.SpotifyToken, error) {
 	auth := base64.StdEncoding.EncodeToString([]byte(c.config.ClientID + ":" + c.config.ClientSecret))
 
-	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.config.AccountsURL+tokenPath, strings.NewReader(data.Encode()))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Add("Authorization", "Basic "+auth)
@@ -102,215 +192,273 @@ func (c *Client) requestToken(ctx context.Context, data url.Values) error {
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		errorBody, _ := io.ReadAll(resp.Body)
+		statusErr := &StatusError{StatusCode: resp.StatusCode, Body: string(errorBody)}
+		// The accounts service answers 400 invalid_grant for revoked or
+		// unknown refresh tokens and authorization codes.
+		var tokenErr tokenError
+		if resp.StatusCode == http.StatusBadRequest &&
+			json.Unmarshal(errorBody, &tokenErr) == nil && tokenErr.Error == "invalid_grant" {
+			return nil, ErrAccountUnlinked.Wrap(statusErr)
+		}
+		return nil, errorForStatus(statusErr)
 	}
 
-	var tokenResponse struct {
-		AccessToken  string `json:"access_token"`
-		TokenType    string `json:"token_type"`
-		ExpiresIn    int    `json:"expires_in"`
-		RefreshToken string `json:"refresh_token,omitempty"`
+	var tokenResponse Token
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return nil, err
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
-		return err
+	return &entity.SpotifyToken{
+		AccessToken:  tokenResponse.AccessToken,
+		RefreshToken: tokenResponse.RefreshToken,
+		TokenType:    tokenResponse.TokenType,
+		Scope:        tokenResponse.Scope,
+		Expiry:       time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// apiURL joins path and escaped path segments onto the configured API base URL.
+func (c *Client) apiURL(path string, segments ...string) string {
+	for _, segment := range segments {
+		path += "/" + url.PathEscape(segment)
 	}
+	return c.config.APIURL + path
+}
 
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+// makeRequest calls a user endpoint with the client's user token source.
+func (c *Client) makeRequest(ctx context.Context, method, url string, body io.Reader, result interface{}) error {
+	return c.doRequest(ctx, c.tokenSource, method, url, body, result)
+}
 
-	c.accessToken = tokenResponse.AccessToken
-	c.tokenExpiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+// makeCatalogRequest calls an endpoint that only needs app credentials.
+func (c *Client) makeCatalogRequest(ctx context.Context, method, url string, body io.Reader, result interface{}) error {
+	return c.doRequest(ctx, c.appTokenSource, method, url, body, result)
+}
 
-	if tokenResponse.RefreshToken != "" {
-		c.refreshToken = tokenResponse.RefreshToken
+// doRequest sends an authorized request, retrying rate-limited, 5xx and
+// network failures with backoff. Failures are reported as the typed errors
+// in errors.go.
+func (c *Client) doRequest(
+	ctx context.Context,
+	tokenSource TokenSource,
+	method, url string,
+	body io.Reader,
+	result interface{},
+) error {
+	if tokenSource == nil {
+		return ErrNoTokenSource
 	}
 
-	return nil
-}
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
 
-func (c *Client) ensureValidToken(ctx context.Context) error {
-	c.mutex.Lock()
-	isValid := c.accessToken != "" && time.Now().Before(c.tokenExpiry)
-	c.mutex.Unlock()
+	retry := c.config.Retry
+	var lastErr error
+
+	for attempt := 1; attempt <= retry.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			delay := retry.backoff(attempt - 1)
+			if wait, ok := RetryAfter(lastErr); ok && wait > 0 {
+				if wait > retry.MaxRetryAfter {
+					c.breaker.failure()
+					return lastErr
+				}
+				delay = wait
+			}
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if !c.breaker.allow() {
+			return ErrCircuitOpen
+		}
 
-	if !isValid {
-		return c.refreshAccessToken(ctx)
+		retryable, err := c.attemptRequest(ctx, tokenSource, method, url, payload, result)
+		if err == nil || !retryable {
+			return err
+		}
+		lastErr = err
 	}
 
-	return nil
+	if _, ok := RetryAfter(lastErr); ok {
+		c.breaker.failure()
+	}
+	return lastErr
 }
 
-func (c *Client) makeRequest(ctx context.Context, method, url string, body io.Reader, result interface{}) error {
-	if err := c.ensureValidToken(ctx); err != nil {
-		return err
+// attemptRequest performs a single request and records its outcome with the
+// circuit breaker. It reports whether a failure is worth retrying.
+func (c *Client) attemptRequest(
+	ctx context.Context,
+	tokenSource TokenSource,
+	method, url string,
+	payload []byte,
+	result interface{},
+) (bool, error) {
+	accessToken, err := tokenSource.AccessToken(ctx)
+	if err != nil {
+		c.breaker.abandon()
+		return false, err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return err
+		c.breaker.abandon()
+		return false, err
 	}
 
-	c.mutex.Lock()
-	req.Header.Add("Authorization", "Bearer "+c.accessToken)
-	c.mutex.Unlock()
+	req.Header.Add("Authorization", "Bearer "+accessToken)
 
-	if body != nil {
+	if payload != nil {
 		req.Header.Add("Content-Type", "application/json")
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		if ctx.Err() != nil {
+			c.breaker.abandon()
+			return false, ctx.Err()
+		}
+		c.breaker.failure()
+		return true, ErrUnavailable.Wrap(err)
 	}
 	defer resp.Body.Close()
 
+	// A rate-limited attempt counts against the breaker only once retrying
+	// it is given up on, so a trial request stays open to its retries.
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		c.breaker.abandon()
+	case resp.StatusCode >= 500:
+		c.breaker.failure()
+	default:
+		c.breaker.success()
+	}
+
 	if resp.StatusCode >= 400 {
 		errorBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Spotify API error: %d %s", resp.StatusCode, string(errorBody))
+		statusErr := &StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(errorBody),
+			RetryAfter: retryAfter(resp.Header),
+		}
+		return isRetryableStatus(resp.StatusCode), errorForStatus(statusErr)
 	}
 
-	if result != nil {
-		return json.NewDecoder(resp.Body).Decode(result)
+	if result != nil && resp.StatusCode != http.StatusNoContent {
+		return false, json.NewDecoder(resp.Body).Decode(result)
 	}
 
-	return nil
+	return false, nil
 }
 
 func (c *Client) GetTrack(ctx context.Context, trackID string) (*entity.Track, error) {
-	var response struct {
-		ID          string `json:"id"`
-		Name        string `json:"name"`
-		Popularity  int    `json:"popularity"`
-		PreviewURL  string `json:"preview_url"`
-		ExternalURL struct {
-			Spotify string `json:"spotify"`
-		} `json:"external_urls"`
-		Album struct {
-			Name   string `json:"name"`
-			Images []struct {
-				URL    string `json:"url"`
-				Height int    `json:"height"`
-				Width  int    `json:"width"`
-			} `json:"images"`
-			ReleaseDate string `json:"release_date"`
-		} `json:"album"`
-		Artists []struct {
-			ID   string `json:"id"`
-			Name string `json:"name"`
-		} `json:"artists"`
-	}
-
-	url := fmt.Sprintf("%s/%s", trackURL, trackID)
-	if err := c.makeRequest(ctx, "GET", url, nil, &response); err != nil {
-		return nil, err
-	}
-
-	releaseDate, err := time.Parse("2006-01-02", response.Album.ReleaseDate)
-	if err != nil {
+	var response trackObject
 
-		releaseDate = time.Now()
+	url := c.apiURL(trackPath, trackID)
+	if err := c.makeCatalogRequest(ctx, "GET", url, nil, &response); err != nil {
+		return nil, err
 	}
 
+	// Spotify has no audio features for some tracks, e.g. very short ones.
 	audioFeatures, err := c.GetAudioFeatures(ctx, trackID)
 	if err != nil {
-
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
 		audioFeatures = valueObject.AudioFeatures{}
 	}
 
-	var artistName string
-	if len(response.Artists) > 0 {
-		artistName = response.Artists[0].Name
-	}
+	track := response.toEntity(audioFeatures)
+	c.withArtistGenres(ctx, []*entity.Track{track})
+	return track, nil
+}
+
+// GetTracksBatch fetches tracks and their audio features using the multi-ID
+// endpoints. Unknown IDs are skipped; the result keeps the order of ids.
+func (c *Client) GetTracksBatch(ctx context.Context, ids []string) ([]*entity.Track, error) {
+	items := make([]trackObject, 0, len(ids))
 
-	var imageURL string
-	if len(response.Album.Images) > 0 {
-		imageURL = response.Album.Images[0].URL
+	for _, chunk := range chunkIDs(ids, maxTracksPerRequest) {
+		var response struct {
+			Tracks []*trackObject `json:"tracks"`
+		}
+
+		url := c.apiURL(trackPath) + "?ids=" + strings.Join(chunk, ",")
+		if err := c.makeCatalogRequest(ctx, "GET", url, nil, &response); err != nil {
+			return nil, err
+		}
+
+		for _, item := range response.Tracks {
+			if item != nil {
+				items = append(items, *item)
+			}
+		}
 	}
-	track := entity.NewTrack(
-		response.ID,
-		response.Name,
-		artistName,
-		response.Album.Name,
-		releaseDate,
-		response.Popularity,
-		audioFeatures,
-		response.PreviewURL,
-		imageURL,
-	)
 
-	return track, nil
+	return c.withTrackDetails(ctx, items), nil
 }
 
 func (c *Client) GetAudioFeatures(ctx context.Context, trackID string) (valueObject.AudioFeatures, error) {
-	var response struct {
-		Danceability     float64 `json:"danceability"`
-		Energy           float64 `json:"energy"`
-		Key              int     `json:"key"`
-		Loudness         float64 `json:"loudness"`
-		Mode             int     `json:"mode"`
-		Speechiness      float64 `json:"speechiness"`
-		Acousticness     float64 `json:"acousticness"`
-		Instrumentalness float64 `json:"instrumentalness"`
-		Liveness         float64 `json:"liveness"`
-		Valence          float64 `json:"valence"`
-		Tempo            float64 `json:"tempo"`
-		Duration         int     `json:"duration_ms"`
-		TimeSignature    int     `json:"time_signature"`
-	}
-
-	url := fmt.Sprintf("%s/%s", audioFeaturesURL, trackID)
-	if err := c.makeRequest(ctx, "GET", url, nil, &response); err != nil {
+	var response audioFeaturesObject
+
+	url := c.apiURL(audioFeaturesPath, trackID)
+	if err := c.makeCatalogRequest(ctx, "GET", url, nil, &response); err != nil {
 		return valueObject.AudioFeatures{}, err
 	}
 
-	return valueObject.AudioFeatures{
-		Danceability:     response.Danceability,
-		Energy:           response.Energy,
-		Key:              response.Key,
-		Loudness:         response.Loudness,
-		Mode:             response.Mode,
-		Speechiness:      response.Speechiness,
-		Acousticness:     response.Acousticness,
-		Instrumentalness: response.Instrumentalness,
-		Liveness:         response.Liveness,
-		Valence:          response.Valence,
-		Tempo:            response.Tempo,
-		Duration:         response.Duration,
-		TimeSignature:    response.TimeSignature,
-	}, nil
+	return response.toValueObject(), nil
+}
+
+// GetAudioFeaturesBatch fetches audio features for many tracks, keyed by
+// Spotify track ID. Tracks without features are missing from the result.
+func (c *Client) GetAudioFeaturesBatch(ctx context.Context, ids []string) (map[string]valueObject.AudioFeatures, error) {
+	features := make(map[string]valueObject.AudioFeatures, len(ids))
+
+	for _, chunk := range chunkIDs(ids, maxAudioFeaturesPerRequest) {
+		var response struct {
+			AudioFeatures []*audioFeaturesObject `json:"audio_features"`
+		}
+
+		url := c.apiURL(audioFeaturesPath) + "?ids=" + strings.Join(chunk, ",")
+		if err := c.makeCatalogRequest(ctx, "GET", url, nil, &response); err != nil {
+			return nil, err
+		}
+
+		for _, item := range response.AudioFeatures {
+			if item != nil {
+				features[item.ID] = item.toValueObject()
+			}
+		}
+	}
+
+	return features, nil
 }
 
 func (c *Client) SearchTracks(ctx context.Context, query string, limit int) ([]*entity.Track, error) {
 	var response struct {
 		Tracks struct {
-			Items []struct {
-				ID          string `json:"id"`
-				Name        string `json:"name"`
-				Popularity  int    `json:"popularity"`
-				PreviewURL  string `json:"preview_url"`
-				ExternalURL struct {
-					Spotify string `json:"spotify"`
-				} `json:"external_urls"`
-				Album struct {
-					Name   string `json:"name"`
-					Images []struct {
-						URL    string `json:"url"`
-						Height int    `json:"height"`
-						Width  int    `json:"width"`
-					} `json:"images"`
-					ReleaseDate string `json:"release_date"`
-				} `json:"album"`
-				Artists []struct {
-					ID   string `json:"id"`
-					Name string `json:"name"`
-				} `json:"artists"`
-			} `json:"items"`
+			Items []trackObject `json:"items"`
 		} `json:"tracks"`
 	}
 
@@ -319,76 +467,17 @@ func (c *Client) SearchTracks(ctx context.Context, query string, limit int) ([]*
 	params.Add("type", "track")
 	params.Add("limit", fmt.Sprintf("%d", limit))
 
-	url := fmt.Sprintf("%s?%s", searchURL, params.Encode())
-	if err := c.makeRequest(ctx, "GET", url, nil, &response); err != nil {
+	url := c.apiURL(searchPath) + "?" + params.Encode()
+	if err := c.makeCatalogRequest(ctx, "GET", url, nil, &response); err != nil {
 		return nil, err
 	}
 
-	tracks := make([]*entity.Track, 0, len(response.Tracks.Items))
-
-	for _, item := range response.Tracks.Items {
-		releaseDate, err := time.Parse("2006-01-02", item.Album.ReleaseDate)
-		if err != nil {
-			releaseDate = time.Now()
-		}
-
-		var artistName string
-		if len(item.Artists) > 0 {
-			artistName = item.Artists[0].Name
-		}
-
-		var imageURL string
-		if len(item.Album.Images) > 0 {
-			imageURL = item.Album.Images[0].URL
-		}
-
-		audioFeatures, err := c.GetAudioFeatures(ctx, item.ID)
-		if err != nil {
-			audioFeatures = valueObject.AudioFeatures{}
-		}
-
-		track := entity.NewTrack(
-			item.ID,
-			item.Name,
-			artistName,
-			item.Album.Name,
-			releaseDate,
-			item.Popularity,
-			audioFeatures,
-			item.PreviewURL,
-			imageURL,
-		)
-
-		tracks = append(tracks, track)
-	}
-
-	return tracks, nil
+	return c.withTrackDetails(ctx, response.Tracks.Items), nil
 }
 
 func (c *Client) GetRecommendations(ctx context.Context, params map[string]string, limit int) ([]*entity.Track, error) {
 	var response struct {
-		Tracks []struct {
-			ID          string `json:"id"`
-			Name        string `json:"name"`
-			Popularity  int    `json:"popularity"`
-			PreviewURL  string `json:"preview_url"`
-			ExternalURL struct {
-				Spotify string `json:"spotify"`
-			} `json:"external_urls"`
-			Album struct {
-				Name   string `json:"name"`
-				Images []struct {
-					URL    string `json:"url"`
-					Height int    `json:"height"`
-					Width  int    `json:"width"`
-				} `json:"images"`
-				ReleaseDate string `json:"release_date"`
-			} `json:"album"`
-			Artists []struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-			} `json:"artists"`
-		} `json:"tracks"`
+		Tracks []trackObject `json:"tracks"`
 	}
 
 	queryParams := url.Values{}
@@ -398,53 +487,150 @@ func (c *Client) GetRecommendations(ctx context.Context, params map[string]strin
 		queryParams.Add(key, value)
 	}
 
-	url := fmt.Sprintf("%s?%s", recommendationURL, queryParams.Encode())
-	if err := c.makeRequest(ctx, "GET", url, nil, &response); err != nil {
+	url := c.apiURL(recommendationPath) + "?" + queryParams.Encode()
+	if err := c.makeCatalogRequest(ctx, "GET", url, nil, &response); err != nil {
 		return nil, err
 	}
 
-	tracks := make([]*entity.Track, 0, len(response.Tracks))
+	return c.withTrackDetails(ctx, response.Tracks), nil
+}
 
-	for _, item := range response.Tracks {
-		releaseDate, err := time.Parse("2006-01-02", item.Album.ReleaseDate)
-		if err != nil {
-			releaseDate = time.Now()
-		}
+// withTrackDetails converts track objects to entities, loading their audio
+// features and artist genres in as few requests as possible. Tracks keep
+// empty features or genres when a lookup fails.
+func (c *Client) withTrackDetails(ctx context.Context, items []trackObject) []*entity.Track {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	features, err := c.GetAudioFeaturesBatch(ctx, ids)
+	if err != nil {
+		features = map[string]valueObject.AudioFeatures{}
+	}
+
+	tracks := make([]*entity.Track, 0, len(items))
+	for _, item := range items {
+		tracks = append(tracks, item.toEntity(features[item.ID]))
+	}
+	c.withArtistGenres(ctx, tracks)
+
+	return tracks
+}
 
-		var artistName string
-		if len(item.Artists) > 0 {
-			artistName = item.Artists[0].Name
+// withArtistGenres sets each track's genres to those of its artists.
+func (c *Client) withArtistGenres(ctx context.Context, tracks []*entity.Track) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, track := range tracks {
+		for _, id := range track.ArtistIDs() {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
 		}
+	}
+
+	artists, err := c.GetArtistsBatch(ctx, ids)
+	if err != nil {
+		return
+	}
 
-		var imageURL string
-		if len(item.Album.Images) > 0 {
-			imageURL = item.Album.Images[0].URL
+	for _, track := range tracks {
+		added := make(map[string]bool)
+		for _, id := range track.ArtistIDs() {
+			for _, genre := range artists[id].Genres {
+				if !added[genre] {
+					added[genre] = true
+					track.Genres = append(track.Genres, genre)
+				}
+			}
 		}
+	}
+}
 
-		audioFeatures, err := c.GetAudioFeatures(ctx, item.ID)
-		if err != nil {
-			audioFeatures = valueObject.AudioFeatures{}
+// GetArtistsBatch fetches full artist objects, keyed by Spotify artist ID.
+// Unknown artists are missing from the result.
+func (c *Client) GetArtistsBatch(ctx context.Context, ids []string) (map[string]Artist, error) {
+	artists := make(map[string]Artist, len(ids))
+
+	for _, chunk := range chunkIDs(ids, maxArtistsPerRequest) {
+		var response struct {
+			Artists []*Artist `json:"artists"`
 		}
 
-		track := entity.NewTrack(
-			item.ID,
-			item.Name,
-			artistName,
-			item.Album.Name,
-			releaseDate,
-			item.Popularity,
-			audioFeatures,
-			item.PreviewURL,
-			imageURL,
-		)
+		url := c.apiURL(artistsPath) + "?ids=" + strings.Join(chunk, ",")
+		if err := c.makeCatalogRequest(ctx, "GET", url, nil, &response); err != nil {
+			return nil, err
+		}
 
-		tracks = append(tracks, track)
+		for _, artist := range response.Artists {
+			if artist != nil {
+				artists[artist.ID] = *artist
+			}
+		}
 	}
 
-	return tracks, nil
+	return artists, nil
+}
+
+func chunkIDs(ids []string, size int) [][]string {
+	chunks := make([][]string, 0, (len(ids)+size-1)/size)
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunks = append(chunks, ids[start:end])
+	}
+	return chunks
 }
 
+// GetRecommendationsByMood returns recommendations for the mood seeded with
+// broad default genres. Prefer GetRecommendationsBySeeds with seeds chosen
+// for the listener.
 func (c *Client) GetRecommendationsByMood(ctx context.Context, mood valueObject.Mood, limit int) ([]*entity.Track, error) {
+	return c.GetRecommendationsBySeeds(ctx, valueObject.DefaultRecommendationSeeds(), mood, limit)
+}
+
+// GetRecommendationsBySeeds returns recommendations based on the seeds and
+// tuned to the mood. Seeds beyond Spotify's limit of five are dropped. Empty
+// seeds return no recommendations rather than falling back to genres the
+// caller may have ruled out.
+func (c *Client) GetRecommendationsBySeeds(
+	ctx context.Context,
+	seeds valueObject.RecommendationSeeds,
+	mood valueObject.Mood,
+	limit int,
+) ([]*entity.Track, error) {
+	if seeds.IsEmpty() {
+		return []*entity.Track{}, nil
+	}
+
+	params := moodParams(mood)
+
+	remaining := valueObject.MaxRecommendationSeeds
+	for _, seed := range []struct {
+		param  string
+		values []string
+	}{
+		{"seed_tracks", seeds.TrackIDs},
+		{"seed_artists", seeds.ArtistIDs},
+		{"seed_genres", seeds.Genres},
+	} {
+		values := seed.values[:min(len(seed.values), remaining)]
+		if len(values) > 0 {
+			params[seed.param] = strings.Join(values, ",")
+			remaining -= len(values)
+		}
+	}
+
+	return c.GetRecommendations(ctx, params, limit)
+}
+
+// moodParams returns the tunable track attributes that steer recommendations
+// toward the mood.
+func moodParams(mood valueObject.Mood) map[string]string {
 	params := make(map[string]string)
 
 	switch mood {
@@ -487,7 +673,322 @@ func (c *Client) GetRecommendationsByMood(ctx context.Context, mood valueObject.
 		params["target_valence"] = "0.5"
 		params["target_energy"] = "0.5"
 	}
-	params["seed_genres"] = "pop,rock,electronic,classical,hip-hop"
 
-	return c.GetRecommendations(ctx, params, limit)
+	return params
+}
+
+// CreatePlaylist creates a playlist owned by spotifyUserID. The client must
+// act on behalf of that user.
+func (c *Client) CreatePlaylist(
+	ctx context.Context,
+	spotifyUserID, name, description string,
+	public bool,
+) (*Playlist, error) {
+	body, err := jsonBody(playlistDetailsRequest{Name: name, Description: description, Public: public})
+	if err != nil {
+		return nil, err
+	}
+
+	var playlist Playlist
+	url := c.apiURL(userPath, spotifyUserID) + playlistsPath
+	if err := c.makeRequest(ctx, "POST", url, body, &playlist); err != nil {
+		return nil, err
+	}
+
+	return &playlist, nil
+}
+
+func (c *Client) UpdatePlaylistDetails(
+	ctx context.Context,
+	playlistID, name, description string,
+	public bool,
+) error {
+	body, err := jsonBody(playlistDetailsRequest{Name: name, Description: description, Public: public})
+	if err != nil {
+		return err
+	}
+
+	return c.makeRequest(ctx, "PUT", c.apiURL(playlistsPath, playlistID), body, nil)
+}
+
+// AddTracksToPlaylist appends tracks to a playlist in as many requests as the
+// per-request limit requires, preserving their order.
+func (c *Client) AddTracksToPlaylist(ctx context.Context, playlistID string, trackIDs []string) error {
+	for _, chunk := range chunkIDs(trackIDs, maxPlaylistTracksPerRequest) {
+		body, err := jsonBody(playlistTracksRequest{URIs: trackURIs(chunk)})
+		if err != nil {
+			return err
+		}
+
+		url := c.apiURL(playlistsPath, playlistID) + trackPath
+		if err := c.makeRequest(ctx, "POST", url, body, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReplacePlaylistTracks sets the playlist's tracks to trackIDs, in order.
+func (c *Client) ReplacePlaylistTracks(ctx context.Context, playlistID string, trackIDs []string) error {
+	first := trackIDs
+	if len(first) > maxPlaylistTracksPerRequest {
+		first = first[:maxPlaylistTracksPerRequest]
+	}
+
+	body, err := jsonBody(playlistTracksRequest{URIs: trackURIs(first)})
+	if err != nil {
+		return err
+	}
+
+	url := c.apiURL(playlistsPath, playlistID) + trackPath
+	if err := c.makeRequest(ctx, "PUT", url, body, nil); err != nil {
+		return err
+	}
+
+	return c.AddTracksToPlaylist(ctx, playlistID, trackIDs[len(first):])
+}
+
+func trackURIs(trackIDs []string) []string {
+	uris := make([]string, len(trackIDs))
+	for i, id := range trackIDs {
+		uris[i] = "spotify:track:" + id
+	}
+	return uris
+}
+
+func jsonBody(payload interface{}) (io.Reader, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
+	}
+	return bytes.NewReader(data), nil
+}
+
+// GetSavedTracks returns a page of the tracks in the user's library, most
+// recently saved first.
+func (c *Client) GetSavedTracks(ctx context.Context, limit, offset int) (*SavedTrackPage, error) {
+	var response struct {
+		Paging
+		Items []struct {
+			AddedAt time.Time    `json:"added_at"`
+			Track   *trackObject `json:"track"`
+		} `json:"items"`
+	}
+
+	url := c.apiURL(mePath+trackPath) + "?" + pageParams(limit, offset)
+	if err := c.makeRequest(ctx, "GET", url, nil, &response); err != nil {
+		return nil, err
+	}
+
+	items := make([]trackObject, 0, len(response.Items))
+	addedAt := make(map[string]time.Time, len(response.Items))
+	for _, item := range response.Items {
+		if item.Track != nil && item.Track.ID != "" {
+			items = append(items, *item.Track)
+			addedAt[item.Track.ID] = item.AddedAt
+		}
+	}
+
+	page := &SavedTrackPage{Paging: response.Paging}
+	for _, track := range c.withTrackDetails(ctx, items) {
+		page.Items = append(page.Items, SavedTrack{AddedAt: addedAt[track.SpotifyID], Track: track})
+	}
+
+	return page, nil
+}
+
+// GetCurrentUserPlaylists returns a page of the playlists the user owns or follows.
+func (c *Client) GetCurrentUserPlaylists(ctx context.Context, limit, offset int) (*PlaylistPage, error) {
+	var page PlaylistPage
+
+	url := c.apiURL(mePath+playlistsPath) + "?" + pageParams(limit, offset)
+	if err := c.makeRequest(ctx, "GET", url, nil, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// GetPlaylistTracks returns a page of a playlist's tracks. Local files and
+// podcast episodes are skipped, so a page may hold fewer items than limit.
+func (c *Client) GetPlaylistTracks(ctx context.Context, playlistID string, limit, offset int) (*TrackPage, error) {
+	var response struct {
+		Paging
+		Items []struct {
+			IsLocal bool         `json:"is_local"`
+			Track   *trackObject `json:"track"`
+		} `json:"items"`
+	}
+
+	url := c.apiURL(playlistsPath, playlistID) + trackPath + "?" + pageParams(limit, offset)
+	if err := c.makeRequest(ctx, "GET", url, nil, &response); err != nil {
+		return nil, err
+	}
+
+	items := make([]trackObject, 0, len(response.Items))
+	for _, item := range response.Items {
+		if !item.IsLocal && item.Track != nil && item.Track.ID != "" {
+			items = append(items, *item.Track)
+		}
+	}
+
+	return &TrackPage{Paging: response.Paging, Items: c.withTrackDetails(ctx, items)}, nil
+}
+
+func pageParams(limit, offset int) string {
+	params := url.Values{}
+	params.Add("limit", strconv.Itoa(limit))
+	params.Add("offset", strconv.Itoa(offset))
+	return params.Encode()
+}
+
+// GetRecentlyPlayed returns up to limit of the user's plays after the given
+// time, or the most recent plays when after is zero. Spotify only keeps the
+// last 50 plays, so callers must sync at least that often to see every play.
+func (c *Client) GetRecentlyPlayed(ctx context.Context, after time.Time, limit int) (*RecentlyPlayedPage, error) {
+	var response struct {
+		Next    string  `json:"next"`
+		Cursors Cursors `json:"cursors"`
+		Items   []struct {
+			PlayedAt time.Time    `json:"played_at"`
+			Track    *trackObject `json:"track"`
+		} `json:"items"`
+	}
+
+	params := url.Values{}
+	params.Add("limit", strconv.Itoa(limit))
+	if !after.IsZero() {
+		params.Add("after", strconv.FormatInt(after.UnixMilli(), 10))
+	}
+
+	url := c.apiURL(mePath+recentlyPlayedPath) + "?" + params.Encode()
+	if err := c.makeRequest(ctx, "GET", url, nil, &response); err != nil {
+		return nil, err
+	}
+
+	items := make([]trackObject, 0, len(response.Items))
+	playedAt := make([]time.Time, 0, len(response.Items))
+	for _, item := range response.Items {
+		if item.Track != nil && item.Track.ID != "" {
+			items = append(items, *item.Track)
+			playedAt = append(playedAt, item.PlayedAt)
+		}
+	}
+
+	page := &RecentlyPlayedPage{Next: response.Next, Cursors: response.Cursors}
+	for i, track := range c.withTrackDetails(ctx, items) {
+		page.Items = append(page.Items, PlayHistoryItem{PlayedAt: playedAt[i], Track: track})
+	}
+
+	return page, nil
+}
+
+// GetTopTracks returns a page of the user's most listened tracks over
+// timeRange: short_term, medium_term or long_term.
+func (c *Client) GetTopTracks(ctx context.Context, timeRange string, limit, offset int) (*TrackPage, error) {
+	var response struct {
+		Paging
+		Items []trackObject `json:"items"`
+	}
+
+	url := c.apiURL(mePath+topTracksPath) + "?" + topParams(timeRange, limit, offset)
+	if err := c.makeRequest(ctx, "GET", url, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return &TrackPage{Paging: response.Paging, Items: c.withTrackDetails(ctx, response.Items)}, nil
+}
+
+// GetTopArtists returns a page of the user's most listened artists over
+// timeRange: short_term, medium_term or long_term.
+func (c *Client) GetTopArtists(ctx context.Context, timeRange string, limit, offset int) (*ArtistPage, error) {
+	var page ArtistPage
+
+	url := c.apiURL(mePath+topArtistsPath) + "?" + topParams(timeRange, limit, offset)
+	if err := c.makeRequest(ctx, "GET", url, nil, &page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+func topParams(timeRange string, limit, offset int) string {
+	return pageParams(limit, offset) + "&" + url.Values{"time_range": {timeRange}}.Encode()
+}
+
+// GetDevices returns the devices the user can play on.
+func (c *Client) GetDevices(ctx context.Context) ([]Device, error) {
+	var response struct {
+		Devices []Device `json:"devices"`
+	}
+
+	if err := c.makeRequest(ctx, "GET", c.apiURL(mePath+devicesPath), nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Devices, nil
+}
+
+// GetPlaybackState returns what the user is playing and where. It returns nil
+// without an error when the user has no active device.
+func (c *Client) GetPlaybackState(ctx context.Context) (*PlaybackState, error) {
+	var response struct {
+		Device     *Device      `json:"device"`
+		IsPlaying  bool         `json:"is_playing"`
+		ProgressMS int          `json:"progress_ms"`
+		Item       *trackObject `json:"item"`
+	}
+
+	if err := c.makeRequest(ctx, "GET", c.apiURL(mePath+playerPath), nil, &response); err != nil {
+		return nil, err
+	}
+	if response.Device == nil {
+		return nil, nil
+	}
+
+	state := &PlaybackState{
+		Device:    *response.Device,
+		IsPlaying: response.IsPlaying,
+		Progress:  time.Duration(response.ProgressMS) * time.Millisecond,
+	}
+	// The item is an episode or missing when no track is playing.
+	if response.Item != nil && response.Item.ID != "" {
+		if tracks := c.withTrackDetails(ctx, []trackObject{*response.Item}); len(tracks) > 0 {
+			state.Track = tracks[0]
+		}
+	}
+
+	return state, nil
+}
+
+// StartPlayback replaces what the user is playing with the tracks, in order,
+// on deviceID, or on the active device when deviceID is empty.
+func (c *Client) StartPlayback(ctx context.Context, deviceID string, trackIDs []string) error {
+	body, err := jsonBody(playbackRequest{URIs: trackURIs(trackIDs)})
+	if err != nil {
+		return err
+	}
+
+	return c.makeRequest(ctx, "PUT", c.apiURL(mePath+playPath)+deviceParams(deviceID, nil), body, nil)
+}
+
+// AddToQueue appends a track to the user's playback queue on deviceID, or on
+// the active device when deviceID is empty.
+func (c *Client) AddToQueue(ctx context.Context, deviceID, trackID string) error {
+	params := url.Values{"uri": {trackURIs([]string{trackID})[0]}}
+	return c.makeRequest(ctx, "POST", c.apiURL(mePath+queuePath)+deviceParams(deviceID, params), nil, nil)
+}
+
+func deviceParams(deviceID string, params url.Values) string {
+	if params == nil {
+		params = url.Values{}
+	}
+	if deviceID != "" {
+		params.Set("device_id", deviceID)
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "?" + params.Encode()
 }
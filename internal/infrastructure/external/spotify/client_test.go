@@ -0,0 +1,118 @@
+package spotify_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"spotify_recommender/internal/domain/domainerr"
+	"spotify_recommender/internal/domain/valueObject"
+	"spotify_recommender/internal/infrastructure/external/spotify"
+	"spotify_recommender/internal/infrastructure/external/spotify/spotifytest"
+	"testing"
+)
+
+func newTestClient(t *testing.T, configure func(*spotify.Config)) (*spotify.Client, *spotifytest.Server) {
+	t.Helper()
+
+	server := spotifytest.NewServer(spotifytest.DefaultFixtures())
+	t.Cleanup(server.Close)
+
+	config := server.Config()
+	if configure != nil {
+		configure(&config)
+	}
+	return spotify.NewClient(config), server
+}
+
+func TestClientBatchesAreChunked(t *testing.T) {
+	client, server := newTestClient(t, nil)
+
+	var ids []string
+	for i := 0; i < 120; i++ {
+		id := fmt.Sprintf("batch-track-%03d", i)
+		server.AddTrack(spotifytest.Track{ID: id, Name: id}, spotifytest.AudioFeatures{Energy: 0.5})
+		ids = append(ids, id)
+	}
+	ids = append(ids, "unknown-track")
+
+	tracks, err := client.GetTracksBatch(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("GetTracksBatch() error = %v", err)
+	}
+
+	if len(tracks) != 120 {
+		t.Fatalf("GetTracksBatch() returned %d tracks, want 120", len(tracks))
+	}
+	for i, track := range tracks {
+		if track.SpotifyID != ids[i] {
+			t.Fatalf("track %d = %q, want %q", i, track.SpotifyID, ids[i])
+		}
+		if track.AudioFeatures.Energy != 0.5 {
+			t.Fatalf("track %q energy = %v, want 0.5", track.SpotifyID, track.AudioFeatures.Energy)
+		}
+	}
+
+	for path, want := range map[string]int{
+		"/v1/tracks":         3,
+		"/v1/audio-features": 2,
+	} {
+		if got := server.RequestCount(path); got != want {
+			t.Errorf("requests to %s = %d, want %d", path, got, want)
+		}
+	}
+}
+
+func TestClientRecommendationsWithoutSeeds(t *testing.T) {
+	client, server := newTestClient(t, nil)
+
+	tracks, err := client.GetRecommendationsBySeeds(
+		context.Background(), valueObject.RecommendationSeeds{}, valueObject.MoodHappy, 10,
+	)
+	if err != nil {
+		t.Fatalf("GetRecommendationsBySeeds() error = %v", err)
+	}
+	if len(tracks) != 0 {
+		t.Errorf("GetRecommendationsBySeeds() returned %d tracks, want none", len(tracks))
+	}
+	if got := server.RequestCount("/v1/recommendations"); got != 0 {
+		t.Errorf("requests to /v1/recommendations = %d, want 0", got)
+	}
+}
+
+func TestClientTokenErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(config *spotify.Config)
+		code      string
+		wantErr   error
+		wantKind  domainerr.Kind
+	}{
+		{
+			name:      "rejected app credentials",
+			configure: func(config *spotify.Config) { config.ClientSecret = "wrong-secret" },
+			code:      spotifytest.AuthorizationCode,
+			wantErr:   spotify.ErrUnauthorized,
+			wantKind:  domainerr.KindUnavailable,
+		},
+		{
+			name:     "invalid authorization code",
+			code:     "unknown-code",
+			wantErr:  spotify.ErrAccountUnlinked,
+			wantKind: domainerr.KindConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, tt.configure)
+
+			_, err := client.ExchangeCodeForToken(context.Background(), tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExchangeCodeForToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if kind := domainerr.KindOf(err); kind != tt.wantKind {
+				t.Errorf("ExchangeCodeForToken() error kind = %q, want %q", kind, tt.wantKind)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package spotify
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"spotify_recommender/internal/domain/domainerr"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRateLimited  = domainerr.Unavailable("spotify_rate_limited", "spotify rate limit exceeded")
+	ErrUnauthorized = domainerr.Unavailable("spotify_unauthorized", "spotify rejected the credentials")
+	ErrNotFound     = domainerr.NotFound("spotify_not_found", "spotify resource not found")
+	ErrUnavailable  = domainerr.Unavailable("spotify_unavailable", "spotify is currently unavailable")
+	ErrCircuitOpen  = domainerr.Unavailable("spotify_circuit_open", "spotify requests are temporarily suspended")
+	ErrRequest      = domainerr.Unavailable("spotify_request_failed", "spotify request failed")
+
+	// ErrAccountUnlinked means Spotify no longer accepts the user's grant,
+	// because it was revoked or the authorization code is invalid, and the
+	// user has to link their account again.
+	ErrAccountUnlinked = domainerr.Conflict("spotify_account_unlinked",
+		"spotify no longer accepts the linked account; link it again")
+)
+
+// StatusError carries the HTTP status and body of a failed Spotify response.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("spotify API error: %d %s", e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+// RetryAfter reports how long Spotify asked us to wait before retrying, if
+// err came from a rate-limited response.
+func RetryAfter(err error) (time.Duration, bool) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests {
+		return statusErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+func errorForStatus(statusErr *StatusError) error {
+	switch {
+	case statusErr.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited.Wrap(statusErr)
+	case statusErr.StatusCode == http.StatusUnauthorized, statusErr.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized.Wrap(statusErr)
+	case statusErr.StatusCode == http.StatusNotFound:
+		return ErrNotFound.Wrap(statusErr)
+	case statusErr.StatusCode >= 500:
+		return ErrUnavailable.Wrap(statusErr)
+	default:
+		return ErrRequest.Wrap(statusErr)
+	}
+}
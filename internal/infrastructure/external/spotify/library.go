@@ -0,0 +1,86 @@
+package spotify
+
+import (
+	"context"
+	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
+)
+
+// Library implements usecase.SpotifyLibrary on top of the client, acting
+// with each user's stored Spotify credentials.
+type Library struct {
+	client      *Client
+	accountRepo repository.SpotifyAccountRepository
+}
+
+func NewLibrary(client *Client, accountRepo repository.SpotifyAccountRepository) *Library {
+	return &Library{
+		client:      client,
+		accountRepo: accountRepo,
+	}
+}
+
+func (l *Library) SavedTracks(ctx context.Context, userID string, offset, limit int) (*usecase.SpotifyTrackPage, error) {
+	page, err := l.client.ForUser(l.accountRepo, userID).GetSavedTracks(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]*entity.Track, 0, len(page.Items))
+	for _, item := range page.Items {
+		tracks = append(tracks, item.Track)
+	}
+
+	return &usecase.SpotifyTrackPage{
+		Tracks:     tracks,
+		Total:      page.Total,
+		NextOffset: offset + limit,
+		HasMore:    page.HasMore(),
+	}, nil
+}
+
+func (l *Library) Playlists(ctx context.Context, userID string, offset, limit int) (*usecase.SpotifyPlaylistPage, error) {
+	page, err := l.client.ForUser(l.accountRepo, userID).GetCurrentUserPlaylists(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	playlists := make([]usecase.SpotifyPlaylistSummary, 0, len(page.Items))
+	for _, item := range page.Items {
+		if item == nil {
+			continue
+		}
+		playlists = append(playlists, usecase.SpotifyPlaylistSummary{
+			ID:          item.ID,
+			Name:        item.Name,
+			Description: item.Description,
+			Public:      item.Public,
+		})
+	}
+
+	return &usecase.SpotifyPlaylistPage{
+		Playlists:  playlists,
+		Total:      page.Total,
+		NextOffset: offset + len(page.Items),
+		HasMore:    page.HasMore(),
+	}, nil
+}
+
+func (l *Library) PlaylistTracks(
+	ctx context.Context,
+	userID, spotifyPlaylistID string,
+	offset, limit int,
+) (*usecase.SpotifyTrackPage, error) {
+	page, err := l.client.ForUser(l.accountRepo, userID).GetPlaylistTracks(ctx, spotifyPlaylistID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usecase.SpotifyTrackPage{
+		Tracks:     page.Items,
+		Total:      page.Total,
+		NextOffset: offset + limit,
+		HasMore:    page.HasMore(),
+	}, nil
+}
@@ -0,0 +1,82 @@
+package spotify
+
+import (
+	"context"
+	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
+	"time"
+)
+
+// ListeningHistory implements usecase.SpotifyListeningHistory on top of the
+// client, acting with each user's stored Spotify credentials.
+type ListeningHistory struct {
+	client      *Client
+	accountRepo repository.SpotifyAccountRepository
+}
+
+func NewListeningHistory(client *Client, accountRepo repository.SpotifyAccountRepository) *ListeningHistory {
+	return &ListeningHistory{
+		client:      client,
+		accountRepo: accountRepo,
+	}
+}
+
+func (h *ListeningHistory) RecentlyPlayed(
+	ctx context.Context,
+	userID string,
+	after time.Time,
+	limit int,
+) (*usecase.SpotifyRecentPlays, error) {
+	page, err := h.client.ForUser(h.accountRepo, userID).GetRecentlyPlayed(ctx, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	plays := make([]usecase.SpotifyPlay, 0, len(page.Items))
+	for _, item := range page.Items {
+		plays = append(plays, usecase.SpotifyPlay{Track: item.Track, PlayedAt: item.PlayedAt})
+	}
+
+	return &usecase.SpotifyRecentPlays{
+		Plays:   plays,
+		HasMore: page.HasMore(),
+	}, nil
+}
+
+func (h *ListeningHistory) TopTracks(
+	ctx context.Context,
+	userID string,
+	timeRange entity.TopTimeRange,
+	limit int,
+) ([]*entity.Track, error) {
+	page, err := h.client.ForUser(h.accountRepo, userID).GetTopTracks(ctx, string(timeRange), limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return page.Items, nil
+}
+
+func (h *ListeningHistory) TopArtists(
+	ctx context.Context,
+	userID string,
+	timeRange entity.TopTimeRange,
+	limit int,
+) ([]usecase.SpotifyArtist, error) {
+	page, err := h.client.ForUser(h.accountRepo, userID).GetTopArtists(ctx, string(timeRange), limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	artists := make([]usecase.SpotifyArtist, 0, len(page.Items))
+	for _, item := range page.Items {
+		artists = append(artists, usecase.SpotifyArtist{
+			ID:     item.ID,
+			Name:   item.Name,
+			Genres: item.Genres,
+		})
+	}
+
+	return artists, nil
+}
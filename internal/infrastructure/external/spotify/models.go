@@ -1,12 +1,33 @@
 package spotify
 
+import (
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/valueObject"
+	"time"
+)
+
 type Token struct {
 	AccessToken  string `json:"access_token"`
 	TokenType    string `json:"token_type"`
+	Scope        string `json:"scope,omitempty"`
 	ExpiresIn    int    `json:"expires_in"`
 	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
+// tokenError is the error body of the accounts service's token endpoint.
+type tokenError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type UserProfile struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email"`
+	Country     string `json:"country"`
+	Product     string `json:"product"`
+}
+
 const (
 	ScopeUserReadPrivate          = "user-read-private"
 	ScopeUserReadEmail            = "user-read-email"
@@ -31,5 +52,246 @@ func CommonScopes() []string {
 		ScopePlaylistModifyPublic,
 		ScopeUserLibraryRead,
 		ScopeUserTopRead,
+		ScopeUserReadRecentlyPlayed,
+		ScopeUserReadPlaybackState,
+		ScopeUserModifyPlaybackState,
+	}
+}
+
+type imageObject struct {
+	URL    string `json:"url"`
+	Height int    `json:"height"`
+	Width  int    `json:"width"`
+}
+
+type artistObject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type trackObject struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Popularity  int    `json:"popularity"`
+	PreviewURL  string `json:"preview_url"`
+	Explicit    bool   `json:"explicit"`
+	DurationMS  int    `json:"duration_ms"`
+	ExternalIDs struct {
+		ISRC string `json:"isrc"`
+	} `json:"external_ids"`
+	ExternalURL struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+	Album struct {
+		Name        string        `json:"name"`
+		Images      []imageObject `json:"images"`
+		ReleaseDate string        `json:"release_date"`
+	} `json:"album"`
+	Artists []artistObject `json:"artists"`
+}
+
+func (t *trackObject) toEntity(audioFeatures valueObject.AudioFeatures) *entity.Track {
+	var imageURL string
+	if len(t.Album.Images) > 0 {
+		imageURL = t.Album.Images[0].URL
+	}
+
+	track := entity.NewTrack(
+		t.ID,
+		t.Name,
+		"",
+		t.Album.Name,
+		time.Time{},
+		t.Popularity,
+		audioFeatures,
+		t.PreviewURL,
+		imageURL,
+	)
+
+	artists := make([]entity.TrackArtist, len(t.Artists))
+	for i, artist := range t.Artists {
+		artists[i] = entity.TrackArtist{ID: artist.ID, Name: artist.Name}
+	}
+	track.SetArtists(artists)
+
+	if releaseDate, precision, ok := parseReleaseDate(t.Album.ReleaseDate); ok {
+		track.ReleaseDate = releaseDate
+		track.ReleaseDatePrecision = precision
+	} else {
+		track.ReleaseDatePrecision = ""
 	}
+
+	track.ISRC = t.ExternalIDs.ISRC
+	track.Explicit = t.Explicit
+	track.DurationMS = t.DurationMS
+	track.ExternalURL = t.ExternalURL.Spotify
+
+	return track
+}
+
+// parseReleaseDate parses an album release date, which Spotify gives as
+// "2006-01-02", "2006-01" or "2006" depending on how much of it is known.
+func parseReleaseDate(value string) (time.Time, entity.ReleaseDatePrecision, bool) {
+	layouts := []struct {
+		layout    string
+		precision entity.ReleaseDatePrecision
+	}{
+		{"2006-01-02", entity.ReleaseDatePrecisionDay},
+		{"2006-01", entity.ReleaseDatePrecisionMonth},
+		{"2006", entity.ReleaseDatePrecisionYear},
+	}
+
+	for _, candidate := range layouts {
+		if date, err := time.Parse(candidate.layout, value); err == nil {
+			return date, candidate.precision, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+type audioFeaturesObject struct {
+	ID               string  `json:"id"`
+	Danceability     float64 `json:"danceability"`
+	Energy           float64 `json:"energy"`
+	Key              int     `json:"key"`
+	Loudness         float64 `json:"loudness"`
+	Mode             int     `json:"mode"`
+	Speechiness      float64 `json:"speechiness"`
+	Acousticness     float64 `json:"acousticness"`
+	Instrumentalness float64 `json:"instrumentalness"`
+	Liveness         float64 `json:"liveness"`
+	Valence          float64 `json:"valence"`
+	Tempo            float64 `json:"tempo"`
+	Duration         int     `json:"duration_ms"`
+	TimeSignature    int     `json:"time_signature"`
+}
+
+func (a *audioFeaturesObject) toValueObject() valueObject.AudioFeatures {
+	return valueObject.AudioFeatures{
+		Danceability:     a.Danceability,
+		Energy:           a.Energy,
+		Key:              a.Key,
+		Loudness:         a.Loudness,
+		Mode:             a.Mode,
+		Speechiness:      a.Speechiness,
+		Acousticness:     a.Acousticness,
+		Instrumentalness: a.Instrumentalness,
+		Liveness:         a.Liveness,
+		Valence:          a.Valence,
+		Tempo:            a.Tempo,
+		Duration:         a.Duration,
+		TimeSignature:    a.TimeSignature,
+	}
+}
+
+type Playlist struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Public       bool              `json:"public"`
+	SnapshotID   string            `json:"snapshot_id"`
+	URI          string            `json:"uri"`
+	ExternalURLs map[string]string `json:"external_urls"`
+	Owner        struct {
+		ID string `json:"id"`
+	} `json:"owner"`
+	Tracks struct {
+		Total int `json:"total"`
+	} `json:"tracks"`
+}
+
+// Paging holds the paging fields shared by Spotify list responses.
+type Paging struct {
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	Total  int    `json:"total"`
+	Next   string `json:"next"`
+}
+
+func (p Paging) HasMore() bool {
+	return p.Next != ""
+}
+
+type SavedTrack struct {
+	AddedAt time.Time
+	Track   *entity.Track
+}
+
+type SavedTrackPage struct {
+	Paging
+	Items []SavedTrack
+}
+
+type PlaylistPage struct {
+	Paging
+	Items []*Playlist `json:"items"`
+}
+
+type TrackPage struct {
+	Paging
+	Items []*entity.Track
+}
+
+type Artist struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Genres     []string `json:"genres"`
+	Popularity int      `json:"popularity"`
+}
+
+type ArtistPage struct {
+	Paging
+	Items []Artist `json:"items"`
+}
+
+// Cursors are the positions of a cursor-paged list, such as recently played
+// tracks, as Unix timestamps in milliseconds.
+type Cursors struct {
+	After  string `json:"after"`
+	Before string `json:"before"`
+}
+
+type PlayHistoryItem struct {
+	PlayedAt time.Time
+	Track    *entity.Track
+}
+
+type RecentlyPlayedPage struct {
+	Items   []PlayHistoryItem
+	Cursors Cursors
+	Next    string
+}
+
+func (p *RecentlyPlayedPage) HasMore() bool {
+	return p.Next != ""
+}
+
+type Device struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	IsActive      bool   `json:"is_active"`
+	IsRestricted  bool   `json:"is_restricted"`
+	VolumePercent int    `json:"volume_percent"`
+}
+
+type PlaybackState struct {
+	Device    Device
+	IsPlaying bool
+	Progress  time.Duration
+	Track     *entity.Track
+}
+
+type playlistDetailsRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Public      bool   `json:"public"`
+}
+
+type playlistTracksRequest struct {
+	URIs []string `json:"uris"`
+}
+
+type playbackRequest struct {
+	URIs []string `json:"uris"`
 }
@@ -0,0 +1,67 @@
+package spotify
+
+import (
+	"context"
+	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/repository"
+)
+
+// Player implements usecase.SpotifyPlayer on top of the client, acting with
+// each user's stored Spotify credentials.
+type Player struct {
+	client      *Client
+	accountRepo repository.SpotifyAccountRepository
+}
+
+func NewPlayer(client *Client, accountRepo repository.SpotifyAccountRepository) *Player {
+	return &Player{
+		client:      client,
+		accountRepo: accountRepo,
+	}
+}
+
+func (p *Player) Devices(ctx context.Context, userID string) ([]usecase.SpotifyDevice, error) {
+	devices, err := p.client.ForUser(p.accountRepo, userID).GetDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]usecase.SpotifyDevice, len(devices))
+	for i, device := range devices {
+		result[i] = toUseCaseDevice(device)
+	}
+	return result, nil
+}
+
+func (p *Player) CurrentPlayback(ctx context.Context, userID string) (*usecase.SpotifyPlayback, error) {
+	state, err := p.client.ForUser(p.accountRepo, userID).GetPlaybackState(ctx)
+	if err != nil || state == nil {
+		return nil, err
+	}
+
+	return &usecase.SpotifyPlayback{
+		Device:    toUseCaseDevice(state.Device),
+		IsPlaying: state.IsPlaying,
+		Progress:  state.Progress,
+		Track:     state.Track,
+	}, nil
+}
+
+func (p *Player) Play(ctx context.Context, userID, deviceID string, spotifyTrackIDs []string) error {
+	return p.client.ForUser(p.accountRepo, userID).StartPlayback(ctx, deviceID, spotifyTrackIDs)
+}
+
+func (p *Player) Queue(ctx context.Context, userID, deviceID, spotifyTrackID string) error {
+	return p.client.ForUser(p.accountRepo, userID).AddToQueue(ctx, deviceID, spotifyTrackID)
+}
+
+func toUseCaseDevice(device Device) usecase.SpotifyDevice {
+	return usecase.SpotifyDevice{
+		ID:            device.ID,
+		Name:          device.Name,
+		Type:          device.Type,
+		IsActive:      device.IsActive,
+		IsRestricted:  device.IsRestricted,
+		VolumePercent: device.VolumePercent,
+	}
+}
@@ -0,0 +1,52 @@
+package spotify
+
+import (
+	"context"
+	"spotify_recommender/internal/domain/repository"
+	"spotify_recommender/internal/domain/service"
+)
+
+// PlaylistPublisher implements service.SpotifyPlaylistPublisher on top of the
+// client, acting with each user's stored Spotify credentials.
+type PlaylistPublisher struct {
+	client      *Client
+	accountRepo repository.SpotifyAccountRepository
+}
+
+func NewPlaylistPublisher(client *Client, accountRepo repository.SpotifyAccountRepository) *PlaylistPublisher {
+	return &PlaylistPublisher{
+		client:      client,
+		accountRepo: accountRepo,
+	}
+}
+
+func (p *PlaylistPublisher) CreatePlaylist(
+	ctx context.Context,
+	userID, spotifyUserID string,
+	details service.SpotifyPlaylistDetails,
+) (string, error) {
+	playlist, err := p.client.ForUser(p.accountRepo, userID).
+		CreatePlaylist(ctx, spotifyUserID, details.Name, details.Description, details.Public)
+	if err != nil {
+		return "", err
+	}
+	return playlist.ID, nil
+}
+
+func (p *PlaylistPublisher) UpdatePlaylist(
+	ctx context.Context,
+	userID, spotifyPlaylistID string,
+	details service.SpotifyPlaylistDetails,
+) error {
+	return p.client.ForUser(p.accountRepo, userID).
+		UpdatePlaylistDetails(ctx, spotifyPlaylistID, details.Name, details.Description, details.Public)
+}
+
+func (p *PlaylistPublisher) ReplaceTracks(
+	ctx context.Context,
+	userID, spotifyPlaylistID string,
+	spotifyTrackIDs []string,
+) error {
+	return p.client.ForUser(p.accountRepo, userID).
+		ReplacePlaylistTracks(ctx, spotifyPlaylistID, spotifyTrackIDs)
+}
@@ -0,0 +1,163 @@
+package spotify_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"spotify_recommender/internal/infrastructure/external/spotify"
+	"spotify_recommender/internal/infrastructure/external/spotify/spotifytest"
+	"testing"
+	"time"
+)
+
+const fixtureTrackID = "4uLU6hMCjMI75M1A2tKUQC"
+
+func TestClientRetries(t *testing.T) {
+	trackPath := "/v1/tracks/" + fixtureTrackID
+
+	tests := []struct {
+		name         string
+		fail         func(server *spotifytest.Server)
+		wantErr      error
+		wantRequests int
+	}{
+		{
+			name:         "succeeds without faults",
+			fail:         func(server *spotifytest.Server) {},
+			wantRequests: 1,
+		},
+		{
+			name: "retries server errors",
+			fail: func(server *spotifytest.Server) {
+				server.FailNext(trackPath, http.StatusBadGateway, 2)
+			},
+			wantRequests: 3,
+		},
+		{
+			name: "gives up after the last attempt",
+			fail: func(server *spotifytest.Server) {
+				server.FailNext(trackPath, http.StatusServiceUnavailable, 3)
+			},
+			wantErr:      spotify.ErrUnavailable,
+			wantRequests: 3,
+		},
+		{
+			name: "waits out Retry-After",
+			fail: func(server *spotifytest.Server) {
+				server.RateLimitNext(trackPath, time.Second, 1)
+			},
+			wantRequests: 2,
+		},
+		{
+			name: "does not wait longer than MaxRetryAfter",
+			fail: func(server *spotifytest.Server) {
+				server.RateLimitNext(trackPath, time.Minute, 1)
+			},
+			wantErr:      spotify.ErrRateLimited,
+			wantRequests: 1,
+		},
+		{
+			name: "does not retry client errors",
+			fail: func(server *spotifytest.Server) {
+				server.FailNext(trackPath, http.StatusBadRequest, 1)
+			},
+			wantErr:      spotify.ErrRequest,
+			wantRequests: 1,
+		},
+		{
+			name: "does not retry missing resources",
+			fail: func(server *spotifytest.Server) {
+				server.FailNext(trackPath, http.StatusNotFound, 1)
+			},
+			wantErr:      spotify.ErrNotFound,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newTestClient(t, nil)
+			tt.fail(server)
+
+			track, err := client.GetTrack(context.Background(), fixtureTrackID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetTrack() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("GetTrack() error = %v", err)
+			} else if track.SpotifyID != fixtureTrackID {
+				t.Fatalf("GetTrack() returned track %q, want %q", track.SpotifyID, fixtureTrackID)
+			}
+
+			if got := server.RequestCount(trackPath); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	trackPath := "/v1/tracks/" + fixtureTrackID
+	const cooldown = 50 * time.Millisecond
+
+	// Every failed attempt counts against the breaker, but a rate-limited
+	// call counts once, when it is given up on.
+	tests := []struct {
+		name     string
+		attempts int
+		fail     func(server *spotifytest.Server)
+	}{
+		{
+			name:     "server errors",
+			attempts: 1,
+			fail: func(server *spotifytest.Server) {
+				server.FailNext(trackPath, http.StatusInternalServerError, 2)
+			},
+		},
+		{
+			name:     "rate limiting beyond MaxRetryAfter",
+			attempts: 3,
+			fail: func(server *spotifytest.Server) {
+				server.RateLimitNext(trackPath, time.Minute, 2)
+			},
+		},
+		{
+			name:     "rate limiting through every attempt",
+			attempts: 3,
+			fail: func(server *spotifytest.Server) {
+				server.FailNext(trackPath, http.StatusTooManyRequests, 2*3)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newTestClient(t, func(config *spotify.Config) {
+				config.Retry.MaxAttempts = tt.attempts
+				config.CircuitBreaker = spotify.CircuitBreakerConfig{FailureThreshold: 2, Cooldown: cooldown}
+			})
+			tt.fail(server)
+			ctx := context.Background()
+
+			for i := 0; i < 2; i++ {
+				if _, err := client.GetTrack(ctx, fixtureTrackID); err == nil || errors.Is(err, spotify.ErrCircuitOpen) {
+					t.Fatalf("GetTrack() #%d error = %v, want the injected failure", i+1, err)
+				}
+			}
+			requests := server.RequestCount(trackPath)
+
+			if _, err := client.GetTrack(ctx, fixtureTrackID); !errors.Is(err, spotify.ErrCircuitOpen) {
+				t.Fatalf("GetTrack() with the breaker open error = %v, want %v", err, spotify.ErrCircuitOpen)
+			}
+			if got := server.RequestCount(trackPath); got != requests {
+				t.Fatalf("requests with the breaker open = %d, want %d", got, requests)
+			}
+
+			time.Sleep(cooldown)
+			if _, err := client.GetTrack(ctx, fixtureTrackID); err != nil {
+				t.Fatalf("GetTrack() after the cooldown error = %v", err)
+			}
+		})
+	}
+}
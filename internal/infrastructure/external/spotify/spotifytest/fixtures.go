@@ -0,0 +1,132 @@
+package spotifytest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+//go:embed fixtures/catalog.json
+var defaultCatalog []byte
+
+type Image struct {
+	URL    string `json:"url"`
+	Height int    `json:"height"`
+	Width  int    `json:"width"`
+}
+
+// Artist mirrors the Spotify artist object. Genres and popularity are only
+// served for full artist objects, such as top artists and catalog lookups.
+type Artist struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Genres     []string `json:"genres,omitempty"`
+	Popularity int      `json:"popularity,omitempty"`
+}
+
+type Album struct {
+	ID                   string  `json:"id"`
+	Name                 string  `json:"name"`
+	Images               []Image `json:"images"`
+	ReleaseDate          string  `json:"release_date"`
+	ReleaseDatePrecision string  `json:"release_date_precision"`
+}
+
+// Track mirrors the Spotify track object as served by the Web API.
+type Track struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Popularity   int               `json:"popularity"`
+	PreviewURL   string            `json:"preview_url"`
+	ExternalURLs map[string]string `json:"external_urls"`
+	Album        Album             `json:"album"`
+	Artists      []Artist          `json:"artists"`
+	DurationMS   int               `json:"duration_ms"`
+	Explicit     bool              `json:"explicit"`
+	ExternalIDs  map[string]string `json:"external_ids"`
+	URI          string            `json:"uri"`
+}
+
+// AudioFeatures mirrors the Spotify audio features object.
+type AudioFeatures struct {
+	ID               string  `json:"id"`
+	Danceability     float64 `json:"danceability"`
+	Energy           float64 `json:"energy"`
+	Key              int     `json:"key"`
+	Loudness         float64 `json:"loudness"`
+	Mode             int     `json:"mode"`
+	Speechiness      float64 `json:"speechiness"`
+	Acousticness     float64 `json:"acousticness"`
+	Instrumentalness float64 `json:"instrumentalness"`
+	Liveness         float64 `json:"liveness"`
+	Valence          float64 `json:"valence"`
+	Tempo            float64 `json:"tempo"`
+	Duration         int     `json:"duration_ms"`
+	TimeSignature    int     `json:"time_signature"`
+}
+
+type User struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email"`
+	Country     string `json:"country"`
+	Product     string `json:"product"`
+}
+
+// SavedTrack is an entry of the fixture user's library.
+type SavedTrack struct {
+	TrackID string    `json:"track_id"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// Play is an entry of the fixture user's listening history.
+type Play struct {
+	TrackID  string    `json:"track_id"`
+	PlayedAt time.Time `json:"played_at"`
+}
+
+// Device is a playback device of the fixture user.
+type Device struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	IsActive      bool   `json:"is_active"`
+	VolumePercent int    `json:"volume_percent"`
+}
+
+// Fixtures is the data a fake server starts with. Artists are the full
+// artist objects of the catalog. Top tracks (by ID) and top artists are keyed
+// by time range.
+type Fixtures struct {
+	User           User                `json:"user"`
+	Tracks         []Track             `json:"tracks"`
+	Artists        []Artist            `json:"artists"`
+	AudioFeatures  []AudioFeatures     `json:"audio_features"`
+	SavedTracks    []SavedTrack        `json:"saved_tracks"`
+	Playlists      []Playlist          `json:"playlists"`
+	RecentlyPlayed []Play              `json:"recently_played"`
+	TopTracks      map[string][]string `json:"top_tracks"`
+	TopArtists     map[string][]Artist `json:"top_artists"`
+	Devices        []Device            `json:"devices"`
+}
+
+// DefaultFixtures returns the bundled catalog: a user profile, a handful of
+// tracks covering a range of moods with their audio features and artists, a
+// library of saved tracks and playlists for the user, their listening history
+// and two idle playback devices.
+func DefaultFixtures() Fixtures {
+	fixtures, err := LoadFixtures(defaultCatalog)
+	if err != nil {
+		panic(err)
+	}
+	return fixtures
+}
+
+func LoadFixtures(data []byte) (Fixtures, error) {
+	var fixtures Fixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return Fixtures{}, fmt.Errorf("failed to parse spotify fixtures: %w", err)
+	}
+	return fixtures, nil
+}
@@ -0,0 +1,1195 @@
+// Package spotifytest provides an in-process fake of the Spotify accounts
+// service and Web API, backed by fixture data, for exercising the Spotify
+// client and the code built on it without network access.
+package spotifytest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"spotify_recommender/internal/infrastructure/external/spotify"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ClientID     = "fake-client-id"
+	ClientSecret = "fake-client-secret"
+	RedirectURI  = "http://localhost/callback"
+
+	// AuthorizationCode is the only code the fake accounts service accepts
+	// in the authorization_code grant.
+	AuthorizationCode = "fake-authorization-code"
+
+	maxIDsPerRequest       = 100
+	maxTrackIDsPerRequest  = 50
+	maxArtistIDsPerRequest = 50
+	maxPlaylistTrackChange = 100
+	maxRecentlyPlayed      = 50
+)
+
+type Playlist struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Public      bool     `json:"public"`
+	OwnerID     string   `json:"owner_id"`
+	SnapshotID  string   `json:"snapshot_id"`
+	TrackURIs   []string `json:"track_uris"`
+}
+
+// PlaybackState is what the fixture user's player is doing: the tracks the
+// last play command started, in order, and the tracks queued after them.
+type PlaybackState struct {
+	DeviceID   string
+	IsPlaying  bool
+	TrackURIs  []string
+	QueuedURIs []string
+}
+
+type fault struct {
+	pathPrefix string
+	status     int
+	retryAfter time.Duration
+	remaining  int
+}
+
+type grant struct {
+	userID    string
+	expiresAt time.Time
+}
+
+// Server is a fake Spotify backend. Use Config to point a spotify.Client at it.
+type Server struct {
+	server *httptest.Server
+
+	mutex         sync.Mutex
+	tokenLifetime time.Duration
+	user          User
+	tracks        map[string]Track
+	trackOrder    []string
+	artists       map[string]Artist
+	audioFeatures map[string]AudioFeatures
+	playlists     map[string]*Playlist
+	playlistOrder []string
+	savedTracks   []SavedTrack
+	plays         []Play
+	topTracks     map[string][]string
+	topArtists    map[string][]Artist
+	devices       []Device
+	playback      PlaybackState
+	accessTokens  map[string]grant
+	refreshTokens map[string]string
+	faults        []*fault
+	requests      map[string]int
+}
+
+func NewServer(fixtures Fixtures) *Server {
+	s := &Server{
+		tokenLifetime: time.Hour,
+		user:          fixtures.User,
+		tracks:        make(map[string]Track),
+		artists:       make(map[string]Artist),
+		audioFeatures: make(map[string]AudioFeatures),
+		playlists:     make(map[string]*Playlist),
+		topTracks:     make(map[string][]string),
+		topArtists:    make(map[string][]Artist),
+		accessTokens:  make(map[string]grant),
+		refreshTokens: make(map[string]string),
+		requests:      make(map[string]int),
+	}
+
+	for _, track := range fixtures.Tracks {
+		s.addTrack(track)
+	}
+	for _, artist := range fixtures.Artists {
+		s.artists[artist.ID] = artist
+	}
+	for _, features := range fixtures.AudioFeatures {
+		s.audioFeatures[features.ID] = features
+	}
+	for _, playlist := range fixtures.Playlists {
+		s.addPlaylist(playlist)
+	}
+	s.savedTracks = append(s.savedTracks, fixtures.SavedTracks...)
+	for _, play := range fixtures.RecentlyPlayed {
+		s.addPlay(play)
+	}
+	for timeRange, trackIDs := range fixtures.TopTracks {
+		s.topTracks[timeRange] = append([]string(nil), trackIDs...)
+	}
+	for timeRange, artists := range fixtures.TopArtists {
+		s.topArtists[timeRange] = append([]Artist(nil), artists...)
+	}
+	s.devices = append(s.devices, fixtures.Devices...)
+	for _, device := range s.devices {
+		if device.IsActive {
+			s.playback.DeviceID = device.ID
+		}
+	}
+
+	s.server = httptest.NewServer(s.routes())
+	return s
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Config returns a client configuration that talks to this server and
+// retries quickly, so injected failures do not slow callers down.
+func (s *Server) Config() spotify.Config {
+	return spotify.Config{
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		RedirectURI:  RedirectURI,
+		AccountsURL:  s.server.URL,
+		APIURL:       s.server.URL + "/v1",
+		Retry: spotify.RetryConfig{
+			MaxAttempts:   3,
+			BaseDelay:     time.Millisecond,
+			MaxDelay:      10 * time.Millisecond,
+			MaxRetryAfter: 5 * time.Second,
+		},
+	}
+}
+
+func (s *Server) AddTrack(track Track, features AudioFeatures) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.addTrack(track)
+	features.ID = track.ID
+	s.audioFeatures[track.ID] = features
+}
+
+func (s *Server) addTrack(track Track) {
+	if track.URI == "" {
+		track.URI = "spotify:track:" + track.ID
+	}
+	if _, exists := s.tracks[track.ID]; !exists {
+		s.trackOrder = append(s.trackOrder, track.ID)
+	}
+	s.tracks[track.ID] = track
+}
+
+// AddPlaylist adds a playlist to the fixture user's library.
+func (s *Server) AddPlaylist(playlist Playlist) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.addPlaylist(playlist)
+}
+
+func (s *Server) addPlaylist(playlist Playlist) {
+	if playlist.OwnerID == "" {
+		playlist.OwnerID = s.user.ID
+	}
+	if playlist.SnapshotID == "" {
+		playlist.SnapshotID = randomID()
+	}
+	if _, exists := s.playlists[playlist.ID]; !exists {
+		s.playlistOrder = append(s.playlistOrder, playlist.ID)
+	}
+	s.playlists[playlist.ID] = &playlist
+}
+
+// SaveTrack adds a track to the front of the fixture user's library.
+func (s *Server) SaveTrack(trackID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.savedTracks = append([]SavedTrack{{TrackID: trackID, AddedAt: time.Now()}}, s.savedTracks...)
+}
+
+// PlayTrack records a play of the track by the fixture user at playedAt.
+func (s *Server) PlayTrack(trackID string, playedAt time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.addPlay(Play{TrackID: trackID, PlayedAt: playedAt})
+}
+
+// addPlay keeps plays sorted newest first and, like Spotify, only the last
+// maxRecentlyPlayed of them.
+func (s *Server) addPlay(play Play) {
+	s.plays = append(s.plays, play)
+	sort.SliceStable(s.plays, func(i, j int) bool {
+		return s.plays[i].PlayedAt.After(s.plays[j].PlayedAt)
+	})
+	if len(s.plays) > maxRecentlyPlayed {
+		s.plays = s.plays[:maxRecentlyPlayed]
+	}
+}
+
+// SetTopTracks replaces the fixture user's top tracks for the time range.
+func (s *Server) SetTopTracks(timeRange string, trackIDs []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.topTracks[timeRange] = append([]string(nil), trackIDs...)
+}
+
+// SetTopArtists replaces the fixture user's top artists for the time range.
+func (s *Server) SetTopArtists(timeRange string, artists []Artist) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.topArtists[timeRange] = append([]Artist(nil), artists...)
+}
+
+// Playback returns the fixture user's current playback.
+func (s *Server) Playback() PlaybackState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	playback := s.playback
+	playback.TrackURIs = append([]string(nil), s.playback.TrackURIs...)
+	playback.QueuedURIs = append([]string(nil), s.playback.QueuedURIs...)
+	return playback
+}
+
+// FailNext makes the next times requests whose path starts with pathPrefix
+// answer with status.
+func (s *Server) FailNext(pathPrefix string, status, times int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = append(s.faults, &fault{pathPrefix: pathPrefix, status: status, remaining: times})
+}
+
+// RateLimitNext makes the next times requests whose path starts with
+// pathPrefix answer 429 with the given Retry-After.
+func (s *Server) RateLimitNext(pathPrefix string, retryAfter time.Duration, times int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = append(s.faults, &fault{
+		pathPrefix: pathPrefix,
+		status:     http.StatusTooManyRequests,
+		retryAfter: retryAfter,
+		remaining:  times,
+	})
+}
+
+// SetTokenLifetime changes the expires_in of tokens issued from now on.
+func (s *Server) SetTokenLifetime(lifetime time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tokenLifetime = lifetime
+}
+
+// ExpireAccessTokens invalidates every access token issued so far.
+func (s *Server) ExpireAccessTokens() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.accessTokens = make(map[string]grant)
+}
+
+// RequestCount returns how many requests were made to path, faults included.
+func (s *Server) RequestCount(path string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.requests[path]
+}
+
+func (s *Server) Playlist(id string) (Playlist, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	playlist, ok := s.playlists[id]
+	if !ok {
+		return Playlist{}, false
+	}
+	copied := *playlist
+	copied.TrackURIs = append([]string(nil), playlist.TrackURIs...)
+	return copied, true
+}
+
+func (s *Server) Playlists() []Playlist {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	playlists := make([]Playlist, 0, len(s.playlistOrder))
+	for _, id := range s.playlistOrder {
+		playlist := *s.playlists[id]
+		playlist.TrackURIs = append([]string(nil), playlist.TrackURIs...)
+		playlists = append(playlists, playlist)
+	}
+	return playlists
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /api/token", s.handleToken)
+
+	mux.HandleFunc("GET /v1/me", s.requireUserToken(s.handleMe))
+	mux.HandleFunc("GET /v1/tracks", s.requireToken(s.handleTracks))
+	mux.HandleFunc("GET /v1/tracks/{id}", s.requireToken(s.handleTrack))
+	mux.HandleFunc("GET /v1/artists", s.requireToken(s.handleArtists))
+	mux.HandleFunc("GET /v1/audio-features", s.requireToken(s.handleAudioFeaturesBatch))
+	mux.HandleFunc("GET /v1/audio-features/{id}", s.requireToken(s.handleAudioFeatures))
+	mux.HandleFunc("GET /v1/search", s.requireToken(s.handleSearch))
+	mux.HandleFunc("GET /v1/recommendations", s.requireToken(s.handleRecommendations))
+
+	mux.HandleFunc("GET /v1/me/tracks", s.requireUserToken(s.handleSavedTracks))
+	mux.HandleFunc("GET /v1/me/playlists", s.requireUserToken(s.handleMyPlaylists))
+	mux.HandleFunc("GET /v1/me/player/recently-played", s.requireUserToken(s.handleRecentlyPlayed))
+	mux.HandleFunc("GET /v1/me/top/tracks", s.requireUserToken(s.handleTopTracks))
+	mux.HandleFunc("GET /v1/me/top/artists", s.requireUserToken(s.handleTopArtists))
+	mux.HandleFunc("GET /v1/me/player", s.requireUserToken(s.handlePlayer))
+	mux.HandleFunc("GET /v1/me/player/devices", s.requireUserToken(s.handleDevices))
+	mux.HandleFunc("PUT /v1/me/player/play", s.requireUserToken(s.handlePlay))
+	mux.HandleFunc("POST /v1/me/player/queue", s.requireUserToken(s.handleQueue))
+	mux.HandleFunc("POST /v1/users/{userID}/playlists", s.requireUserToken(s.handleCreatePlaylist))
+	mux.HandleFunc("GET /v1/playlists/{id}", s.requireUserToken(s.handleGetPlaylist))
+	mux.HandleFunc("PUT /v1/playlists/{id}", s.requireUserToken(s.handleUpdatePlaylist))
+	mux.HandleFunc("GET /v1/playlists/{id}/tracks", s.requireUserToken(s.handleGetPlaylistTracks))
+	mux.HandleFunc("POST /v1/playlists/{id}/tracks", s.requireUserToken(s.handleAddPlaylistTracks))
+	mux.HandleFunc("PUT /v1/playlists/{id}/tracks", s.requireUserToken(s.handleReplacePlaylistTracks))
+
+	return s.injectFaults(mux)
+}
+
+func (s *Server) injectFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		s.requests[r.URL.Path]++
+
+		var injected *fault
+		for _, f := range s.faults {
+			if f.remaining > 0 && strings.HasPrefix(r.URL.Path, f.pathPrefix) {
+				f.remaining--
+				injected = f
+				break
+			}
+		}
+		s.mutex.Unlock()
+
+		if injected == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if injected.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(injected.retryAfter.Seconds())))
+		}
+		writeAPIError(w, injected.status, http.StatusText(injected.status))
+	})
+}
+
+// requireToken accepts any valid access token, user or client-credentials.
+func (s *Server) requireToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.authorize(r); !ok {
+			writeAPIError(w, http.StatusUnauthorized, "Invalid access token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireUserToken accepts only access tokens issued to the fixture user.
+func (s *Server) requireUserToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := s.authorize(r)
+		if !ok || userID == "" {
+			writeAPIError(w, http.StatusUnauthorized, "Invalid access token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) authorize(r *http.Request) (string, bool) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return "", false
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	g, ok := s.accessTokens[token]
+	if !ok || time.Now().After(g.expiresAt) {
+		return "", false
+	}
+	return g.userID, true
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != ClientID || clientSecret != ClientSecret {
+		writeTokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch r.PostForm.Get("grant_type") {
+	case "client_credentials":
+		writeJSON(w, http.StatusOK, s.issueToken("", ""))
+	case "authorization_code":
+		if r.PostForm.Get("code") != AuthorizationCode {
+			writeTokenError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+		refreshToken := randomID()
+		s.refreshTokens[refreshToken] = s.user.ID
+		writeJSON(w, http.StatusOK, s.issueToken(s.user.ID, refreshToken))
+	case "refresh_token":
+		userID, ok := s.refreshTokens[r.PostForm.Get("refresh_token")]
+		if !ok {
+			writeTokenError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.issueToken(userID, ""))
+	default:
+		writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+	}
+}
+
+func (s *Server) issueToken(userID, refreshToken string) spotify.Token {
+	accessToken := randomID()
+	s.accessTokens[accessToken] = grant{userID: userID, expiresAt: time.Now().Add(s.tokenLifetime)}
+
+	return spotify.Token{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.tokenLifetime.Seconds()),
+		RefreshToken: refreshToken,
+	}
+}
+
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	writeJSON(w, http.StatusOK, s.user)
+}
+
+func (s *Server) handleTrack(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	track, ok := s.tracks[r.PathValue("id")]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "Non existing id")
+		return
+	}
+	writeJSON(w, http.StatusOK, track)
+}
+
+func (s *Server) handleTracks(w http.ResponseWriter, r *http.Request) {
+	ids, ok := parseIDs(w, r, maxTrackIDsPerRequest)
+	if !ok {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tracks := make([]*Track, 0, len(ids))
+	for _, id := range ids {
+		if track, ok := s.tracks[id]; ok {
+			tracks = append(tracks, &track)
+		} else {
+			tracks = append(tracks, nil)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tracks": tracks})
+}
+
+func (s *Server) handleArtists(w http.ResponseWriter, r *http.Request) {
+	ids, ok := parseIDs(w, r, maxArtistIDsPerRequest)
+	if !ok {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	artists := make([]*Artist, 0, len(ids))
+	for _, id := range ids {
+		if artist, ok := s.artists[id]; ok {
+			artists = append(artists, &artist)
+		} else {
+			artists = append(artists, nil)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"artists": artists})
+}
+
+func (s *Server) handleAudioFeatures(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	features, ok := s.audioFeatures[r.PathValue("id")]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "analysis not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, features)
+}
+
+func (s *Server) handleAudioFeaturesBatch(w http.ResponseWriter, r *http.Request) {
+	ids, ok := parseIDs(w, r, maxIDsPerRequest)
+	if !ok {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	features := make([]*AudioFeatures, 0, len(ids))
+	for _, id := range ids {
+		if f, ok := s.audioFeatures[id]; ok {
+			features = append(features, &f)
+		} else {
+			features = append(features, nil)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"audio_features": features})
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("q"))
+	limit := queryInt(r, "limit", 20)
+	offset := queryInt(r, "offset", 0)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	matches := make([]Track, 0)
+	for _, id := range s.trackOrder {
+		track := s.tracks[id]
+		if query == "" || strings.Contains(strings.ToLower(track.Name), query) || matchesArtist(track, query) {
+			matches = append(matches, track)
+		}
+	}
+
+	start, end := pageBounds(len(matches), limit, offset)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tracks": pageObject(matches[start:end], limit, start, len(matches)),
+	})
+}
+
+// handleRecommendations returns fixture tracks whose audio features satisfy
+// the min_ and max_ tunable attributes in the query. Seeds are ignored.
+// handleRecommendations requires one to five seeds, like Spotify, and serves
+// catalog tracks within the min_ and max_ attribute bounds.
+func (s *Server) handleRecommendations(w http.ResponseWriter, r *http.Request) {
+	limit := queryInt(r, "limit", 20)
+	query := r.URL.Query()
+
+	var seeds []map[string]string
+	for param, seedType := range map[string]string{
+		"seed_tracks":  "TRACK",
+		"seed_artists": "ARTIST",
+		"seed_genres":  "GENRE",
+	} {
+		if query.Get(param) == "" {
+			continue
+		}
+		for _, id := range strings.Split(query.Get(param), ",") {
+			seeds = append(seeds, map[string]string{"id": id, "type": seedType})
+		}
+	}
+	if len(seeds) == 0 || len(seeds) > 5 {
+		writeAPIError(w, http.StatusBadRequest, "Invalid request: between 1 and 5 seeds are required")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tracks := make([]Track, 0, limit)
+	for _, id := range s.trackOrder {
+		if len(tracks) >= limit {
+			break
+		}
+		if features, ok := s.audioFeatures[id]; ok && withinBounds(features, query) {
+			tracks = append(tracks, s.tracks[id])
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tracks": tracks, "seeds": seeds})
+}
+
+func (s *Server) handleSavedTracks(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	items := make([]interface{}, 0, len(s.savedTracks))
+	for _, saved := range s.savedTracks {
+		items = append(items, map[string]interface{}{
+			"added_at": saved.AddedAt,
+			"track":    s.tracks[saved.TrackID],
+		})
+	}
+
+	s.writePage(w, r, items, 20)
+}
+
+func (s *Server) handleMyPlaylists(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	items := make([]interface{}, 0, len(s.playlistOrder))
+	for _, id := range s.playlistOrder {
+		items = append(items, s.playlistObject(s.playlists[id]))
+	}
+
+	s.writePage(w, r, items, 20)
+}
+
+// handleRecentlyPlayed serves plays newest first. With an after cursor it
+// serves the limit plays that directly follow the cursor, and a next link
+// while newer plays remain.
+func (s *Server) handleRecentlyPlayed(w http.ResponseWriter, r *http.Request) {
+	limit := queryInt(r, "limit", 20)
+	if limit < 1 || limit > maxRecentlyPlayed {
+		writeAPIError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+	after := queryInt(r, "after", -1)
+	before := queryInt(r, "before", -1)
+	if after >= 0 && before >= 0 {
+		writeAPIError(w, http.StatusBadRequest, "Only one of after and before may be set")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var plays []Play
+	for _, play := range s.plays {
+		playedAt := int(play.PlayedAt.UnixMilli())
+		if (after < 0 || playedAt > after) && (before < 0 || playedAt < before) {
+			plays = append(plays, play)
+		}
+	}
+
+	more := len(plays) > limit
+	if more {
+		if after >= 0 {
+			plays = plays[len(plays)-limit:]
+		} else {
+			plays = plays[:limit]
+		}
+	}
+
+	items := make([]map[string]interface{}, 0, len(plays))
+	for _, play := range plays {
+		items = append(items, map[string]interface{}{
+			"played_at": play.PlayedAt.UTC().Format("2006-01-02T15:04:05.000Z"),
+			"track":     s.tracks[play.TrackID],
+		})
+	}
+
+	response := map[string]interface{}{
+		"items":   items,
+		"limit":   limit,
+		"next":    nil,
+		"cursors": nil,
+	}
+	if len(plays) > 0 {
+		newest := strconv.FormatInt(plays[0].PlayedAt.UnixMilli(), 10)
+		oldest := strconv.FormatInt(plays[len(plays)-1].PlayedAt.UnixMilli(), 10)
+		response["cursors"] = map[string]string{"after": newest, "before": oldest}
+
+		if more {
+			next := *r.URL
+			query := next.Query()
+			if after >= 0 {
+				query.Set("after", newest)
+			} else {
+				query.Set("before", oldest)
+			}
+			next.RawQuery = query.Encode()
+			response["next"] = s.server.URL + next.RequestURI()
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleTopTracks(w http.ResponseWriter, r *http.Request) {
+	timeRange, ok := parseTimeRange(w, r)
+	if !ok {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	items := make([]interface{}, 0, len(s.topTracks[timeRange]))
+	for _, id := range s.topTracks[timeRange] {
+		if track, ok := s.tracks[id]; ok {
+			items = append(items, track)
+		}
+	}
+
+	s.writePage(w, r, items, 20)
+}
+
+func (s *Server) handleTopArtists(w http.ResponseWriter, r *http.Request) {
+	timeRange, ok := parseTimeRange(w, r)
+	if !ok {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	items := make([]interface{}, 0, len(s.topArtists[timeRange]))
+	for _, artist := range s.topArtists[timeRange] {
+		items = append(items, map[string]interface{}{
+			"id":         artist.ID,
+			"name":       artist.Name,
+			"genres":     artist.Genres,
+			"popularity": artist.Popularity,
+			"uri":        "spotify:artist:" + artist.ID,
+		})
+	}
+
+	s.writePage(w, r, items, 20)
+}
+
+func (s *Server) handleDevices(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"devices": s.devices})
+}
+
+// handlePlayer serves the playback state, or 204 with no body when no device
+// is active, as Spotify does.
+func (s *Server) handlePlayer(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	device, ok := s.device(s.playback.DeviceID)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var item interface{}
+	if len(s.playback.TrackURIs) > 0 {
+		if track, ok := s.tracks[strings.TrimPrefix(s.playback.TrackURIs[0], "spotify:track:")]; ok {
+			item = track
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"device":      device,
+		"is_playing":  s.playback.IsPlaying,
+		"progress_ms": 0,
+		"item":        item,
+	})
+}
+
+func (s *Server) handlePlay(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		URIs []string `json:"uris"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "Malformed json")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceID, ok := s.targetDevice(w, r)
+	if !ok {
+		return
+	}
+
+	s.activateDevice(deviceID)
+	s.playback.IsPlaying = true
+	if len(request.URIs) > 0 {
+		s.playback.TrackURIs = request.URIs
+		s.playback.QueuedURIs = nil
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleQueue(w http.ResponseWriter, r *http.Request) {
+	uri := r.URL.Query().Get("uri")
+	if !strings.HasPrefix(uri, "spotify:track:") {
+		writeAPIError(w, http.StatusBadRequest, "Invalid uri")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deviceID, ok := s.targetDevice(w, r)
+	if !ok {
+		return
+	}
+
+	s.activateDevice(deviceID)
+	s.playback.QueuedURIs = append(s.playback.QueuedURIs, uri)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// targetDevice resolves the device_id of a player command, defaulting to the
+// active device, and writes Spotify's 404 when there is none.
+func (s *Server) targetDevice(w http.ResponseWriter, r *http.Request) (string, bool) {
+	deviceID := r.URL.Query().Get("device_id")
+	if deviceID == "" {
+		deviceID = s.playback.DeviceID
+		if deviceID == "" {
+			writeAPIError(w, http.StatusNotFound, "Player command failed: No active device found")
+			return "", false
+		}
+	}
+
+	if _, ok := s.device(deviceID); !ok {
+		writeAPIError(w, http.StatusNotFound, "Device not found")
+		return "", false
+	}
+	return deviceID, true
+}
+
+func (s *Server) device(id string) (Device, bool) {
+	for _, device := range s.devices {
+		if id != "" && device.ID == id {
+			return device, true
+		}
+	}
+	return Device{}, false
+}
+
+func (s *Server) activateDevice(id string) {
+	for i := range s.devices {
+		s.devices[i].IsActive = s.devices[i].ID == id
+	}
+	s.playback.DeviceID = id
+}
+
+func (s *Server) handleCreatePlaylist(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Public      *bool  `json:"public"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Name == "" {
+		writeAPIError(w, http.StatusBadRequest, "Missing required field: name")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if r.PathValue("userID") != s.user.ID {
+		writeAPIError(w, http.StatusForbidden, "You cannot create a playlist for another user")
+		return
+	}
+
+	playlist := &Playlist{
+		ID:          randomID()[:22],
+		Name:        request.Name,
+		Description: request.Description,
+		Public:      request.Public == nil || *request.Public,
+		OwnerID:     s.user.ID,
+		SnapshotID:  randomID(),
+	}
+	s.playlists[playlist.ID] = playlist
+	s.playlistOrder = append(s.playlistOrder, playlist.ID)
+
+	writeJSON(w, http.StatusCreated, s.playlistObject(playlist))
+}
+
+func (s *Server) handleGetPlaylist(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	playlist, ok := s.playlists[r.PathValue("id")]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.playlistObject(playlist))
+}
+
+func (s *Server) handleUpdatePlaylist(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Public      *bool   `json:"public"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "Error parsing JSON.")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	playlist, ok := s.playlists[r.PathValue("id")]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	if request.Name != nil {
+		playlist.Name = *request.Name
+	}
+	if request.Description != nil {
+		playlist.Description = *request.Description
+	}
+	if request.Public != nil {
+		playlist.Public = *request.Public
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleGetPlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	playlist, ok := s.playlists[r.PathValue("id")]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+
+	items := make([]interface{}, 0, len(playlist.TrackURIs))
+	for _, uri := range playlist.TrackURIs {
+		track, ok := s.tracks[strings.TrimPrefix(uri, "spotify:track:")]
+		if !ok {
+			track = Track{URI: uri}
+		}
+		items = append(items, map[string]interface{}{"track": track})
+	}
+
+	s.writePage(w, r, items, 100)
+}
+
+func (s *Server) handleAddPlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	s.changePlaylistTracks(w, r, false)
+}
+
+func (s *Server) handleReplacePlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	s.changePlaylistTracks(w, r, true)
+}
+
+func (s *Server) changePlaylistTracks(w http.ResponseWriter, r *http.Request, replace bool) {
+	var request struct {
+		URIs     []string `json:"uris"`
+		Position *int     `json:"position"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "Error parsing JSON.")
+		return
+	}
+	if len(request.URIs) > maxPlaylistTrackChange {
+		writeAPIError(w, http.StatusBadRequest, "Too many ids requested")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	playlist, ok := s.playlists[r.PathValue("id")]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+
+	switch {
+	case replace:
+		playlist.TrackURIs = append([]string(nil), request.URIs...)
+	case request.Position != nil && *request.Position < len(playlist.TrackURIs):
+		position := *request.Position
+		uris := append([]string(nil), playlist.TrackURIs[:position]...)
+		uris = append(uris, request.URIs...)
+		playlist.TrackURIs = append(uris, playlist.TrackURIs[position:]...)
+	default:
+		playlist.TrackURIs = append(playlist.TrackURIs, request.URIs...)
+	}
+	playlist.SnapshotID = randomID()
+
+	status := http.StatusCreated
+	if replace {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, map[string]string{"snapshot_id": playlist.SnapshotID})
+}
+
+func (s *Server) playlistObject(playlist *Playlist) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            playlist.ID,
+		"name":          playlist.Name,
+		"description":   playlist.Description,
+		"public":        playlist.Public,
+		"snapshot_id":   playlist.SnapshotID,
+		"owner":         map[string]string{"id": playlist.OwnerID},
+		"uri":           "spotify:playlist:" + playlist.ID,
+		"external_urls": map[string]string{"spotify": "https://open.spotify.com/playlist/" + playlist.ID},
+		"tracks":        map[string]int{"total": len(playlist.TrackURIs)},
+	}
+}
+
+func matchesArtist(track Track, query string) bool {
+	for _, artist := range track.Artists {
+		if strings.Contains(strings.ToLower(artist.Name), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func withinBounds(features AudioFeatures, query map[string][]string) bool {
+	attributes := map[string]float64{
+		"danceability":     features.Danceability,
+		"energy":           features.Energy,
+		"valence":          features.Valence,
+		"acousticness":     features.Acousticness,
+		"instrumentalness": features.Instrumentalness,
+		"speechiness":      features.Speechiness,
+		"liveness":         features.Liveness,
+		"tempo":            features.Tempo,
+	}
+
+	for name, value := range attributes {
+		if bound, err := strconv.ParseFloat(first(query["min_"+name]), 64); err == nil && value < bound {
+			return false
+		}
+		if bound, err := strconv.ParseFloat(first(query["max_"+name]), 64); err == nil && value > bound {
+			return false
+		}
+	}
+	return true
+}
+
+// pageBounds clamps a limit/offset window to a list of total items.
+func pageBounds(total, limit, offset int) (int, int) {
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return offset, end
+}
+
+func pageObject(items interface{}, limit, offset, total int) map[string]interface{} {
+	return map[string]interface{}{
+		"items":  items,
+		"limit":  limit,
+		"offset": offset,
+		"total":  total,
+	}
+}
+
+// writePage writes the requested window of items as a paging object, with a
+// next link when more items follow.
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, items []interface{}, defaultLimit int) {
+	limit := queryInt(r, "limit", defaultLimit)
+	start, end := pageBounds(len(items), limit, queryInt(r, "offset", 0))
+
+	page := pageObject(items[start:end], limit, start, len(items))
+	page["next"] = nil
+	if end < len(items) {
+		next := *r.URL
+		query := next.Query()
+		query.Set("offset", strconv.Itoa(end))
+		next.RawQuery = query.Encode()
+		page["next"] = s.server.URL + next.RequestURI()
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+func parseTimeRange(w http.ResponseWriter, r *http.Request) (string, bool) {
+	timeRange := r.URL.Query().Get("time_range")
+	switch timeRange {
+	case "":
+		return "medium_term", true
+	case "short_term", "medium_term", "long_term":
+		return timeRange, true
+	default:
+		writeAPIError(w, http.StatusBadRequest, "Invalid time range")
+		return "", false
+	}
+}
+
+func parseIDs(w http.ResponseWriter, r *http.Request, max int) ([]string, bool) {
+	raw := r.URL.Query().Get("ids")
+	if raw == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid request")
+		return nil, false
+	}
+
+	ids := strings.Split(raw, ",")
+	if len(ids) > max {
+		writeAPIError(w, http.StatusBadRequest, "Too many ids requested")
+		return nil, false
+	}
+	return ids, true
+}
+
+func queryInt(r *http.Request, name string, defaultValue int) int {
+	value, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func randomID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{"status": status, "message": message},
+	})
+}
+
+func writeTokenError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
@@ -0,0 +1,168 @@
+package spotify
+
+import (
+	"context"
+	"spotify_recommender/internal/domain/domainerr"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoTokenSource = domainerr.Unavailable("spotify_not_configured", "spotify client has no credentials configured")
+	ErrNotConnected  = domainerr.Conflict("spotify_not_connected", "spotify account is not connected")
+)
+
+type TokenSource interface {
+	AccessToken(ctx context.Context) (string, error)
+}
+
+type staticTokenSource struct {
+	token *entity.SpotifyToken
+}
+
+// StaticTokenSource always returns the given token and never refreshes it.
+func StaticTokenSource(token *entity.SpotifyToken) TokenSource {
+	return &staticTokenSource{token: token}
+}
+
+func (s *staticTokenSource) AccessToken(ctx context.Context) (string, error) {
+	return s.token.AccessToken, nil
+}
+
+// UserTokenSource serves a user's stored access token, refreshing it when it
+// is about to expire and persisting the refreshed token back to storage.
+// Without an account repository there are no stored tokens to serve.
+type UserTokenSource struct {
+	client      *Client
+	accountRepo repository.SpotifyAccountRepository
+	userID      string
+
+	mutex sync.Mutex
+	token *entity.SpotifyToken
+}
+
+func NewUserTokenSource(
+	client *Client,
+	accountRepo repository.SpotifyAccountRepository,
+	userID string,
+) *UserTokenSource {
+	return &UserTokenSource{
+		client:      client,
+		accountRepo: accountRepo,
+		userID:      userID,
+	}
+}
+
+func (s *UserTokenSource) AccessToken(ctx context.Context) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.token == nil {
+		if s.accountRepo == nil {
+			return "", ErrNoTokenSource
+		}
+		token, err := s.accountRepo.GetToken(ctx, s.userID)
+		if err != nil {
+			return "", ErrNotConnected.Wrap(err)
+		}
+		s.token = token
+	}
+
+	if s.token.Valid() {
+		return s.token.AccessToken, nil
+	}
+
+	refreshed, err := s.client.refreshUserToken(ctx, s.token.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+
+	refreshed.UserID = s.token.UserID
+	refreshed.SpotifyUserID = s.token.SpotifyUserID
+	refreshed.CreatedAt = s.token.CreatedAt
+	if refreshed.Scope == "" {
+		refreshed.Scope = s.token.Scope
+	}
+
+	if err := s.accountRepo.SaveToken(ctx, refreshed); err != nil {
+		return "", err
+	}
+
+	s.token = refreshed
+	return s.token.AccessToken, nil
+}
+
+// tokenRefreshTimeout bounds a shared refresh. The refresh runs detached from
+// the caller that started it so one cancelled request does not fail the
+// others waiting on the same token.
+const tokenRefreshTimeout = 10 * time.Second
+
+type tokenRefresh struct {
+	done  chan struct{}
+	token *entity.SpotifyToken
+	err   error
+}
+
+// ClientCredentialsTokenSource serves app-level tokens obtained with the
+// client-credentials grant. It is used for catalog endpoints that do not
+// need a user. Concurrent callers share a single in-flight refresh.
+type ClientCredentialsTokenSource struct {
+	client *Client
+
+	mutex    sync.Mutex
+	token    *entity.SpotifyToken
+	inflight *tokenRefresh
+}
+
+func NewClientCredentialsTokenSource(client *Client) *ClientCredentialsTokenSource {
+	return &ClientCredentialsTokenSource{
+		client: client,
+	}
+}
+
+func (s *ClientCredentialsTokenSource) AccessToken(ctx context.Context) (string, error) {
+	s.mutex.Lock()
+	if s.token.Valid() {
+		accessToken := s.token.AccessToken
+		s.mutex.Unlock()
+		return accessToken, nil
+	}
+
+	call := s.inflight
+	if call == nil {
+		call = &tokenRefresh{done: make(chan struct{})}
+		s.inflight = call
+		go s.refresh(call)
+	}
+	s.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return "", call.err
+		}
+		return call.token.AccessToken, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (s *ClientCredentialsTokenSource) refresh(call *tokenRefresh) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
+
+	token, err := s.client.requestClientCredentialsToken(ctx)
+
+	s.mutex.Lock()
+	if err == nil {
+		s.token = token
+	}
+	s.inflight = nil
+	s.mutex.Unlock()
+
+	call.token = token
+	call.err = err
+	close(call.done)
+}
@@ -0,0 +1,204 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/valueObject"
+	"spotify_recommender/internal/infrastructure/cache"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	currentWeatherKeyPrefix = "weather:current:"
+	forecastKeyPrefix       = "weather:forecast:"
+)
+
+// CacheConfig sets how long current weather and forecasts are cached and the
+// geohash precision of the tiles they are cached for.
+type CacheConfig struct {
+	TTL         time.Duration
+	ForecastTTL time.Duration
+	Precision   int
+}
+
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		TTL:         10 * time.Minute,
+		ForecastTTL: time.Hour,
+		Precision:   5,
+	}
+}
+
+// CachedReporter caches the reports of another usecase.WeatherReporter per
+// geohash tile, so users close to each other share one lookup. Concurrent
+// lookups for a tile that is not cached yet wait for a single upstream call,
+// made for the center of the tile.
+type CachedReporter struct {
+	reporter usecase.WeatherReporter
+	cache    cache.Cache
+	config   CacheConfig
+
+	mutex    sync.Mutex
+	inflight map[string]*tileLookup
+}
+
+type tileLookup struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+func NewCachedReporter(reporter usecase.WeatherReporter, cache cache.Cache, config CacheConfig) *CachedReporter {
+	defaults := DefaultCacheConfig()
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.ForecastTTL <= 0 {
+		config.ForecastTTL = defaults.ForecastTTL
+	}
+	if config.Precision <= 0 {
+		config.Precision = defaults.Precision
+	}
+
+	return &CachedReporter{
+		reporter: reporter,
+		cache:    cache,
+		config:   config,
+		inflight: make(map[string]*tileLookup),
+	}
+}
+
+func (r *CachedReporter) GetCurrentWeather(ctx context.Context, latitude, longitude float64) (valueObject.Weather, error) {
+	report, err := r.ReportCurrentWeather(ctx, latitude, longitude)
+	if err != nil {
+		return "", err
+	}
+	return report.Weather, nil
+}
+
+func (r *CachedReporter) ReportCurrentWeather(
+	ctx context.Context,
+	latitude, longitude float64,
+) (usecase.WeatherReport, error) {
+	if !ValidCoordinates(latitude, longitude) {
+		return usecase.WeatherReport{}, ErrInvalidCoordinates
+	}
+
+	tile := Geohash(latitude, longitude, r.config.Precision)
+
+	var report usecase.WeatherReport
+	if r.cached(ctx, currentWeatherKeyPrefix+tile, &report) && valueObject.ValidWeather(report.Weather) {
+		return report, nil
+	}
+
+	value, err := r.wait(ctx, r.lookup(ctx, currentWeatherKeyPrefix, tile, r.config.TTL,
+		func(ctx context.Context, latitude, longitude float64) (interface{}, error) {
+			return r.reporter.ReportCurrentWeather(ctx, latitude, longitude)
+		}))
+	if err != nil {
+		return usecase.WeatherReport{}, err
+	}
+	return value.(usecase.WeatherReport), nil
+}
+
+func (r *CachedReporter) ReportHourlyForecast(
+	ctx context.Context,
+	latitude, longitude float64,
+) (usecase.ForecastReport, error) {
+	if !ValidCoordinates(latitude, longitude) {
+		return usecase.ForecastReport{}, ErrInvalidCoordinates
+	}
+
+	tile := Geohash(latitude, longitude, r.config.Precision)
+
+	var report usecase.ForecastReport
+	if r.cached(ctx, forecastKeyPrefix+tile, &report) && len(report.Forecasts) > 0 {
+		return report, nil
+	}
+
+	value, err := r.wait(ctx, r.lookup(ctx, forecastKeyPrefix, tile, r.config.ForecastTTL,
+		func(ctx context.Context, latitude, longitude float64) (interface{}, error) {
+			return r.reporter.ReportHourlyForecast(ctx, latitude, longitude)
+		}))
+	if err != nil {
+		return usecase.ForecastReport{}, err
+	}
+	return value.(usecase.ForecastReport), nil
+}
+
+func (r *CachedReporter) wait(ctx context.Context, lookup *tileLookup) (interface{}, error) {
+	select {
+	case <-lookup.done:
+		return lookup.value, lookup.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// lookup returns the upstream lookup for the tile under the key prefix,
+// starting one with fetch unless it is already in flight. The lookup outlives a cancelled caller so
+// the others waiting on it still get an answer.
+func (r *CachedReporter) lookup(
+	ctx context.Context,
+	prefix, tile string,
+	ttl time.Duration,
+	fetch func(ctx context.Context, latitude, longitude float64) (interface{}, error),
+) *tileLookup {
+	key := prefix + tile
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if lookup, ok := r.inflight[key]; ok {
+		return lookup
+	}
+
+	lookup := &tileLookup{done: make(chan struct{})}
+	r.inflight[key] = lookup
+
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+		latitude, longitude := GeohashCenter(tile)
+
+		lookup.value, lookup.err = fetch(ctx, latitude, longitude)
+		if lookup.err == nil {
+			r.store(ctx, key, lookup.value, ttl)
+		}
+
+		r.mutex.Lock()
+		delete(r.inflight, key)
+		r.mutex.Unlock()
+		close(lookup.done)
+	}()
+
+	return lookup
+}
+
+// cached decodes the value cached under key into value and reports whether
+// there was one. Cache failures are logged and treated as misses.
+func (r *CachedReporter) cached(ctx context.Context, key string, value interface{}) bool {
+	data, ok, err := r.cache.Get(ctx, key)
+	if err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("failed to read cached weather")
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	return json.Unmarshal(data, value) == nil
+}
+
+func (r *CachedReporter) store(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if err := r.cache.Set(ctx, key, data, ttl); err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("failed to cache weather")
+	}
+}
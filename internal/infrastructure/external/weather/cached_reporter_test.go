@@ -0,0 +1,133 @@
+package weather_test
+
+import (
+	"context"
+	"spotify_recommender/internal/infrastructure/cache"
+	"spotify_recommender/internal/infrastructure/external/weather"
+	"spotify_recommender/internal/infrastructure/external/weather/openmeteo"
+	"spotify_recommender/internal/infrastructure/external/weather/weathertest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestCachedReporter(t *testing.T) (*weather.CachedReporter, *weathertest.Server) {
+	t.Helper()
+
+	server := newTestServer(t)
+	registry := weather.NewRegistry(weather.DefaultHealthConfig(), weather.Provider{
+		Name:    openmeteo.ProviderName,
+		Service: openmeteo.NewClient(server.OpenMeteoConfig()),
+	})
+	return weather.NewCachedReporter(registry, cache.NewMemoryCache(), weather.DefaultCacheConfig()), server
+}
+
+func TestCachedReporterSharesTiles(t *testing.T) {
+	// Offsets are from the center of a tile, which is about 0.04 degrees
+	// across at the default precision.
+	tileLatitude, tileLongitude := weather.GeohashCenter(
+		weather.Geohash(londonLatitude, londonLongitude, weather.DefaultCacheConfig().Precision),
+	)
+
+	tests := []struct {
+		name            string
+		latitudeOffset  float64
+		longitudeOffset float64
+		wantRequests    int
+	}{
+		{
+			name:         "same coordinates",
+			wantRequests: 1,
+		},
+		{
+			name:            "nearby coordinates in the same tile",
+			latitudeOffset:  0.01,
+			longitudeOffset: -0.01,
+			wantRequests:    1,
+		},
+		{
+			name:           "coordinates in another tile",
+			latitudeOffset: 0.2,
+			wantRequests:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, server := newTestCachedReporter(t)
+			ctx := context.Background()
+
+			if _, err := reporter.ReportCurrentWeather(ctx, tileLatitude, tileLongitude); err != nil {
+				t.Fatalf("ReportCurrentWeather() error = %v", err)
+			}
+			report, err := reporter.ReportCurrentWeather(
+				ctx, tileLatitude+tt.latitudeOffset, tileLongitude+tt.longitudeOffset,
+			)
+			if err != nil {
+				t.Fatalf("ReportCurrentWeather() error = %v", err)
+			}
+			if report.Source != openmeteo.ProviderName {
+				t.Errorf("ReportCurrentWeather() source = %q, want %q", report.Source, openmeteo.ProviderName)
+			}
+
+			if got := server.Requests(weathertest.OpenMeteoPath); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestCachedReporterCoalescesConcurrentLookups(t *testing.T) {
+	reporter, server := newTestCachedReporter(t)
+	server.DelayNext(weathertest.OpenMeteoPath, 100*time.Millisecond, 1)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := reporter.ReportCurrentWeather(context.Background(), londonLatitude, londonLongitude)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("ReportCurrentWeather() error = %v", err)
+		}
+	}
+	if got := server.Requests(weathertest.OpenMeteoPath); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	tests := []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		precision int
+		want      string
+	}{
+		{name: "london", latitude: 51.5074, longitude: -0.1278, precision: 5, want: "gcpvj"},
+		{name: "sydney", latitude: -33.8688, longitude: 151.2093, precision: 5, want: "r3gx2"},
+		{name: "origin", latitude: 0, longitude: 0, precision: 3, want: "s00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := weather.Geohash(tt.latitude, tt.longitude, tt.precision)
+			if hash != tt.want {
+				t.Fatalf("Geohash() = %q, want %q", hash, tt.want)
+			}
+
+			latitude, longitude := weather.GeohashCenter(hash)
+			if got := weather.Geohash(latitude, longitude, tt.precision); got != hash {
+				t.Errorf("Geohash(GeohashCenter(%q)) = %q", hash, got)
+			}
+		})
+	}
+}
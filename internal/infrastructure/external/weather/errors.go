@@ -0,0 +1,45 @@
+package weather
+
+import (
+	"fmt"
+	"net/http"
+	"spotify_recommender/internal/domain/domainerr"
+	"strings"
+)
+
+var (
+	ErrNotConfigured      = domainerr.Unavailable("weather_not_configured", "no weather api key is configured")
+	ErrInvalidCoordinates = domainerr.Validation("invalid_coordinates", "latitude must be between -90 and 90 and longitude between -180 and 180")
+	ErrRateLimited        = domainerr.Unavailable("weather_rate_limited", "weather api rate limit exceeded")
+	ErrUnauthorized       = domainerr.Unavailable("weather_unauthorized", "weather api rejected the api key")
+	ErrNotFound           = domainerr.NotFound("weather_not_found", "no weather found for the location")
+	ErrUnavailable        = domainerr.Unavailable("weather_unavailable", "weather api is currently unavailable")
+	ErrRequest            = domainerr.Unavailable("weather_request_failed", "weather request failed")
+
+	ErrNoProviderAvailable = domainerr.Unavailable("weather_no_provider", "no weather provider could determine the weather")
+)
+
+// StatusError carries the HTTP status and body of a failed weather response.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("weather API error: %d %s", e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+func errorForStatus(statusErr *StatusError) error {
+	switch {
+	case statusErr.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited.Wrap(statusErr)
+	case statusErr.StatusCode == http.StatusUnauthorized, statusErr.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized.Wrap(statusErr)
+	case statusErr.StatusCode == http.StatusNotFound:
+		return ErrNotFound.Wrap(statusErr)
+	case statusErr.StatusCode >= 500:
+		return ErrUnavailable.Wrap(statusErr)
+	default:
+		return ErrRequest.Wrap(statusErr)
+	}
+}
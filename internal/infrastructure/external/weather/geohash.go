@@ -0,0 +1,80 @@
+package weather
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Geohash encodes the coordinates as a geohash of precision characters. Every
+// point in the same tile shares the hash; five characters make tiles of
+// roughly 5 by 5 km.
+func Geohash(latitude, longitude float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLon, maxLon := -180.0, 180.0
+
+	hash := make([]byte, 0, precision)
+	even := true
+	bit, index := 0, 0
+
+	for len(hash) < precision {
+		if even {
+			mid := (minLon + maxLon) / 2
+			if longitude >= mid {
+				index = index<<1 | 1
+				minLon = mid
+			} else {
+				index <<= 1
+				maxLon = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if latitude >= mid {
+				index = index<<1 | 1
+				minLat = mid
+			} else {
+				index <<= 1
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		if bit++; bit == 5 {
+			hash = append(hash, geohashAlphabet[index])
+			bit, index = 0, 0
+		}
+	}
+
+	return string(hash)
+}
+
+// GeohashCenter returns the coordinates of the center of the geohash's tile.
+func GeohashCenter(hash string) (latitude, longitude float64) {
+	minLat, maxLat := -90.0, 90.0
+	minLon, maxLon := -180.0, 180.0
+	even := true
+
+	for i := 0; i < len(hash); i++ {
+		index := 0
+		for index < len(geohashAlphabet) && geohashAlphabet[index] != hash[i] {
+			index++
+		}
+
+		for mask := 16; mask > 0; mask >>= 1 {
+			if even {
+				mid := (minLon + maxLon) / 2
+				if index&mask != 0 {
+					minLon = mid
+				} else {
+					maxLon = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if index&mask != 0 {
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	return (minLat + maxLat) / 2, (minLon + maxLon) / 2
+}
@@ -0,0 +1,50 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// GetJSON sends a GET request with the given headers and decodes the JSON
+// response into result. Failures are reported as the typed errors in
+// errors.go, so every provider fails the same way.
+func GetJSON(ctx context.Context, httpClient *http.Client, url string, header http.Header, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create weather request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return ErrUnavailable.Wrap(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		errorBody, _ := io.ReadAll(resp.Body)
+		return errorForStatus(&StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(errorBody),
+		})
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return ErrRequest.Wrap(fmt.Errorf("failed to decode weather response: %w", err))
+	}
+	return nil
+}
+
+// ValidCoordinates reports whether latitude and longitude are within range.
+func ValidCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
@@ -0,0 +1,127 @@
+// Package metno implements weather lookups with the MET Norway
+// Locationforecast API, which needs no API key but requires an identifying
+// User-Agent.
+package metno
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/valueObject"
+	"spotify_recommender/internal/infrastructure/external/weather"
+	"strconv"
+	"time"
+)
+
+const (
+	// ProviderName identifies MET Norway in the provider registry.
+	ProviderName = "met.no"
+
+	DefaultBaseURL   = "https://api.met.no/weatherapi"
+	DefaultTimeout   = 5 * time.Second
+	DefaultUserAgent = "spotify-recommender/1.0"
+
+	locationForecastPath = "/locationforecast/2.0/compact"
+)
+
+type Config struct {
+	BaseURL   string
+	Timeout   time.Duration
+	UserAgent string
+}
+
+// Client talks to the MET Norway Locationforecast API. Observations are
+// always metric.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+func NewClient(config Config) *Client {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent
+	}
+
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// GetCurrentWeather returns the weather context at the coordinates,
+// classified from the current observation.
+func (c *Client) GetCurrentWeather(ctx context.Context, latitude, longitude float64) (valueObject.Weather, error) {
+	observation, err := c.GetCurrentObservation(ctx, latitude, longitude)
+	if err != nil {
+		return "", err
+	}
+	return observation.Classify(), nil
+}
+
+// GetCurrentObservation returns the first step of the forecast, which is the
+// current hour. MET Norway does not report an apparent temperature, so
+// FeelsLike is the air temperature.
+func (c *Client) GetCurrentObservation(
+	ctx context.Context,
+	latitude, longitude float64,
+) (valueObject.WeatherObservation, error) {
+	steps, err := c.getForecast(ctx, latitude, longitude)
+	if err != nil {
+		return valueObject.WeatherObservation{}, err
+	}
+	return steps[0].toObservation(), nil
+}
+
+// GetHourlyForecast returns the weather forecast for the next nine or so
+// days, hourly for the first two or three of them and six-hourly after.
+func (c *Client) GetHourlyForecast(ctx context.Context, latitude, longitude float64) ([]usecase.WeatherForecast, error) {
+	steps, err := c.getForecast(ctx, latitude, longitude)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts := make([]usecase.WeatherForecast, len(steps))
+	for i, step := range steps {
+		forecasts[i] = usecase.WeatherForecast{
+			Time:    step.Time,
+			Weather: step.toObservation().Classify(),
+		}
+	}
+	return forecasts, nil
+}
+
+func (c *Client) getForecast(ctx context.Context, latitude, longitude float64) ([]forecastStep, error) {
+	if !weather.ValidCoordinates(latitude, longitude) {
+		return nil, weather.ErrInvalidCoordinates
+	}
+
+	// MET Norway rejects coordinates with more than four decimals.
+	params := url.Values{}
+	params.Add("lat", strconv.FormatFloat(latitude, 'f', 4, 64))
+	params.Add("lon", strconv.FormatFloat(longitude, 'f', 4, 64))
+
+	header := http.Header{}
+	header.Set("User-Agent", c.config.UserAgent)
+
+	var response forecastResponse
+	url := c.config.BaseURL + locationForecastPath + "?" + params.Encode()
+	if err := weather.GetJSON(ctx, c.httpClient, url, header, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Properties.Timeseries) == 0 {
+		return nil, weather.ErrRequest.Wrap(
+			errors.New("met.no returned an empty forecast"))
+	}
+
+	return response.Properties.Timeseries, nil
+}
@@ -0,0 +1,89 @@
+package metno
+
+import (
+	"spotify_recommender/internal/domain/valueObject"
+	"strings"
+	"time"
+)
+
+type forecastStep struct {
+	Time time.Time `json:"time"`
+	Data struct {
+		Instant struct {
+			Details struct {
+				AirTemperature    float64 `json:"air_temperature"`
+				CloudAreaFraction float64 `json:"cloud_area_fraction"`
+				RelativeHumidity  float64 `json:"relative_humidity"`
+				WindSpeed         float64 `json:"wind_speed"`
+			} `json:"details"`
+		} `json:"instant"`
+		NextHour        period `json:"next_1_hours"`
+		NextSixHours    period `json:"next_6_hours"`
+		NextTwelveHours period `json:"next_12_hours"`
+	} `json:"data"`
+}
+
+// period summarizes the weather over the hours following a forecast step.
+type period struct {
+	Summary struct {
+		SymbolCode string `json:"symbol_code"`
+	} `json:"summary"`
+}
+
+type forecastResponse struct {
+	Properties struct {
+		Timeseries []forecastStep `json:"timeseries"`
+	} `json:"properties"`
+}
+
+func (s forecastStep) toObservation() valueObject.WeatherObservation {
+	details := s.Data.Instant.Details
+	return valueObject.WeatherObservation{
+		ConditionCode: conditionCode(s.symbol()),
+		Temperature:   details.AirTemperature,
+		FeelsLike:     details.AirTemperature,
+		WindSpeed:     details.WindSpeed,
+		Humidity:      int(details.RelativeHumidity + 0.5),
+		CloudCover:    int(details.CloudAreaFraction + 0.5),
+		Units:         valueObject.WeatherUnitsMetric,
+	}
+}
+
+// symbol returns the weather symbol of the shortest period following the
+// step. Steps more than a couple of days ahead are six hours apart and only
+// summarize longer periods.
+func (s forecastStep) symbol() string {
+	for _, period := range []period{s.Data.NextHour, s.Data.NextSixHours, s.Data.NextTwelveHours} {
+		if period.Summary.SymbolCode != "" {
+			return period.Summary.SymbolCode
+		}
+	}
+	return ""
+}
+
+// conditionCode translates a MET Norway weather symbol, such as
+// "lightrainshowers_day", to the closest OpenWeatherMap condition code.
+func conditionCode(symbol string) int {
+	symbol, _, _ = strings.Cut(symbol, "_")
+
+	switch {
+	case strings.Contains(symbol, "thunder"):
+		return 211
+	case strings.Contains(symbol, "sleet"):
+		return 611
+	case strings.Contains(symbol, "snow"):
+		return 601
+	case strings.Contains(symbol, "rain"):
+		return 501
+	case symbol == "fog":
+		return 741
+	case symbol == "fair":
+		return 801
+	case symbol == "partlycloudy":
+		return 802
+	case symbol == "cloudy":
+		return 804
+	default:
+		return 800
+	}
+}
@@ -0,0 +1,121 @@
+// Package openmeteo implements weather lookups with the Open-Meteo forecast
+// API, which needs no API key.
+package openmeteo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/valueObject"
+	"spotify_recommender/internal/infrastructure/external/weather"
+	"strconv"
+	"time"
+)
+
+const (
+	// ProviderName identifies Open-Meteo in the provider registry.
+	ProviderName = "open-meteo"
+
+	DefaultBaseURL = "https://api.open-meteo.com/v1"
+	DefaultTimeout = 5 * time.Second
+
+	forecastPath     = "/forecast"
+	currentVariables = "temperature_2m,apparent_temperature,relative_humidity_2m,cloud_cover,wind_speed_10m,weather_code"
+	// forecastDays is the longest forecast Open-Meteo makes.
+	forecastDays = 16
+)
+
+type Config struct {
+	BaseURL string
+	Timeout time.Duration
+}
+
+// Client talks to the Open-Meteo forecast API. Observations are always
+// metric.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+func NewClient(config Config) *Client {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// GetCurrentWeather returns the weather context at the coordinates,
+// classified from the current observation.
+func (c *Client) GetCurrentWeather(ctx context.Context, latitude, longitude float64) (valueObject.Weather, error) {
+	observation, err := c.GetCurrentObservation(ctx, latitude, longitude)
+	if err != nil {
+		return "", err
+	}
+	return observation.Classify(), nil
+}
+
+func (c *Client) GetCurrentObservation(
+	ctx context.Context,
+	latitude, longitude float64,
+) (valueObject.WeatherObservation, error) {
+	if !weather.ValidCoordinates(latitude, longitude) {
+		return valueObject.WeatherObservation{}, weather.ErrInvalidCoordinates
+	}
+
+	params := url.Values{}
+	params.Add("latitude", strconv.FormatFloat(latitude, 'f', -1, 64))
+	params.Add("longitude", strconv.FormatFloat(longitude, 'f', -1, 64))
+	params.Add("current", currentVariables)
+	params.Add("wind_speed_unit", "ms")
+
+	var response forecastResponse
+	url := c.config.BaseURL + forecastPath + "?" + params.Encode()
+	if err := weather.GetJSON(ctx, c.httpClient, url, nil, &response); err != nil {
+		return valueObject.WeatherObservation{}, err
+	}
+
+	return response.Current.toObservation(), nil
+}
+
+// GetHourlyForecast returns the weather forecast for every hour of the next
+// 16 days, starting at midnight UTC today.
+func (c *Client) GetHourlyForecast(ctx context.Context, latitude, longitude float64) ([]usecase.WeatherForecast, error) {
+	if !weather.ValidCoordinates(latitude, longitude) {
+		return nil, weather.ErrInvalidCoordinates
+	}
+
+	params := url.Values{}
+	params.Add("latitude", strconv.FormatFloat(latitude, 'f', -1, 64))
+	params.Add("longitude", strconv.FormatFloat(longitude, 'f', -1, 64))
+	params.Add("hourly", currentVariables)
+	params.Add("forecast_days", strconv.Itoa(forecastDays))
+	params.Add("timeformat", "unixtime")
+	params.Add("timezone", "GMT")
+	params.Add("wind_speed_unit", "ms")
+
+	var response forecastResponse
+	url := c.config.BaseURL + forecastPath + "?" + params.Encode()
+	if err := weather.GetJSON(ctx, c.httpClient, url, nil, &response); err != nil {
+		return nil, err
+	}
+
+	hours := response.Hourly.hours()
+	forecasts := make([]usecase.WeatherForecast, len(hours))
+	for i, hour := range hours {
+		forecasts[i] = usecase.WeatherForecast{
+			Time:    hour,
+			Weather: response.Hourly.observation(i).Classify(),
+		}
+	}
+	return forecasts, nil
+}
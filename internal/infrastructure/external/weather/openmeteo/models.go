@@ -0,0 +1,101 @@
+package openmeteo
+
+import (
+	"spotify_recommender/internal/domain/valueObject"
+	"time"
+)
+
+type currentConditions struct {
+	Temperature float64 `json:"temperature_2m"`
+	FeelsLike   float64 `json:"apparent_temperature"`
+	Humidity    int     `json:"relative_humidity_2m"`
+	CloudCover  int     `json:"cloud_cover"`
+	WindSpeed   float64 `json:"wind_speed_10m"`
+	WeatherCode int     `json:"weather_code"`
+}
+
+// hourlyConditions holds one value per forecast hour in each variable, the
+// hours given as Unix times.
+type hourlyConditions struct {
+	Time        []int64   `json:"time"`
+	Temperature []float64 `json:"temperature_2m"`
+	FeelsLike   []float64 `json:"apparent_temperature"`
+	Humidity    []int     `json:"relative_humidity_2m"`
+	CloudCover  []int     `json:"cloud_cover"`
+	WindSpeed   []float64 `json:"wind_speed_10m"`
+	WeatherCode []int     `json:"weather_code"`
+}
+
+type forecastResponse struct {
+	Current currentConditions `json:"current"`
+	Hourly  hourlyConditions  `json:"hourly"`
+}
+
+func (c currentConditions) toObservation() valueObject.WeatherObservation {
+	return valueObject.WeatherObservation{
+		ConditionCode: conditionCode(c.WeatherCode),
+		Temperature:   c.Temperature,
+		FeelsLike:     c.FeelsLike,
+		WindSpeed:     c.WindSpeed,
+		Humidity:      c.Humidity,
+		CloudCover:    c.CloudCover,
+		Units:         valueObject.WeatherUnitsMetric,
+	}
+}
+
+// hours returns the forecast hours in order, leaving out any hour a variable
+// has no value for.
+func (h hourlyConditions) hours() []time.Time {
+	count := min(len(h.Time), len(h.Temperature), len(h.FeelsLike), len(h.Humidity),
+		len(h.CloudCover), len(h.WindSpeed), len(h.WeatherCode))
+
+	hours := make([]time.Time, count)
+	for i := range hours {
+		hours[i] = time.Unix(h.Time[i], 0).UTC()
+	}
+	return hours
+}
+
+func (h hourlyConditions) observation(i int) valueObject.WeatherObservation {
+	return currentConditions{
+		Temperature: h.Temperature[i],
+		FeelsLike:   h.FeelsLike[i],
+		Humidity:    h.Humidity[i],
+		CloudCover:  h.CloudCover[i],
+		WindSpeed:   h.WindSpeed[i],
+		WeatherCode: h.WeatherCode[i],
+	}.toObservation()
+}
+
+// conditionCode translates a WMO weather interpretation code, which
+// Open-Meteo reports, to the closest OpenWeatherMap condition code.
+func conditionCode(wmoCode int) int {
+	switch {
+	case wmoCode == 0:
+		return 800 // clear sky
+	case wmoCode == 1:
+		return 801
+	case wmoCode == 2:
+		return 802
+	case wmoCode == 3:
+		return 804
+	case wmoCode == 45 || wmoCode == 48:
+		return 741 // fog
+	case wmoCode >= 51 && wmoCode <= 57:
+		return 301 // drizzle
+	case wmoCode == 66 || wmoCode == 67:
+		return 511 // freezing rain
+	case wmoCode >= 61 && wmoCode <= 65:
+		return 501 // rain
+	case wmoCode >= 71 && wmoCode <= 77:
+		return 601 // snow
+	case wmoCode >= 80 && wmoCode <= 82:
+		return 521 // rain showers
+	case wmoCode == 85 || wmoCode == 86:
+		return 621 // snow showers
+	case wmoCode >= 95:
+		return 211 // thunderstorm
+	default:
+		return 800
+	}
+}
@@ -0,0 +1,133 @@
+// Package openweathermap implements weather lookups with the OpenWeatherMap
+// current weather and 5 day / 3 hour forecast APIs.
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/valueObject"
+	"spotify_recommender/internal/infrastructure/external/weather"
+	"strconv"
+	"time"
+)
+
+const (
+	// ProviderName identifies OpenWeatherMap in the provider registry.
+	ProviderName = "openweathermap"
+
+	DefaultBaseURL = "https://api.openweathermap.org/data/2.5"
+	DefaultUnits   = "metric"
+	DefaultTimeout = 5 * time.Second
+
+	currentWeatherPath = "/weather"
+	forecastPath       = "/forecast"
+)
+
+// Config configures the OpenWeatherMap client. Units is one of "standard",
+// "metric" or "imperial", as understood by the API.
+type Config struct {
+	APIKey  string
+	Units   string
+	BaseURL string
+	Timeout time.Duration
+}
+
+// Client talks to the OpenWeatherMap current weather and forecast APIs.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+func NewClient(config Config) *Client {
+	if !valueObject.ValidWeatherUnits(valueObject.WeatherUnits(config.Units)) {
+		config.Units = DefaultUnits
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// GetCurrentWeather returns the weather context at the coordinates,
+// classified from the current observation.
+func (c *Client) GetCurrentWeather(ctx context.Context, latitude, longitude float64) (valueObject.Weather, error) {
+	observation, err := c.GetCurrentObservation(ctx, latitude, longitude)
+	if err != nil {
+		return "", err
+	}
+	return observation.Classify(), nil
+}
+
+// GetCurrentObservation returns the weather measured at the coordinates, in
+// the configured units.
+func (c *Client) GetCurrentObservation(
+	ctx context.Context,
+	latitude, longitude float64,
+) (valueObject.WeatherObservation, error) {
+	var response currentWeatherResponse
+	if err := c.get(ctx, currentWeatherPath, latitude, longitude, &response); err != nil {
+		return valueObject.WeatherObservation{}, err
+	}
+	if len(response.Conditions) == 0 {
+		return valueObject.WeatherObservation{}, weather.ErrRequest.Wrap(
+			errors.New("openweathermap returned no weather conditions"))
+	}
+
+	return response.toObservation(valueObject.WeatherUnits(c.config.Units)), nil
+}
+
+// GetHourlyForecast returns the weather forecast for the next five days. The
+// free OpenWeatherMap plans forecast in three-hour steps, not hourly.
+func (c *Client) GetHourlyForecast(ctx context.Context, latitude, longitude float64) ([]usecase.WeatherForecast, error) {
+	var response forecastResponse
+	if err := c.get(ctx, forecastPath, latitude, longitude, &response); err != nil {
+		return nil, err
+	}
+
+	units := valueObject.WeatherUnits(c.config.Units)
+	forecasts := make([]usecase.WeatherForecast, 0, len(response.List))
+	for _, step := range response.List {
+		if len(step.Conditions) == 0 {
+			continue
+		}
+		forecasts = append(forecasts, usecase.WeatherForecast{
+			Time:    time.Unix(step.Time, 0).UTC(),
+			Weather: step.toObservation(units).Classify(),
+		})
+	}
+	if len(forecasts) == 0 {
+		return nil, weather.ErrRequest.Wrap(
+			errors.New("openweathermap returned an empty forecast"))
+	}
+	return forecasts, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, latitude, longitude float64, result interface{}) error {
+	if c.config.APIKey == "" {
+		return weather.ErrNotConfigured
+	}
+	if !weather.ValidCoordinates(latitude, longitude) {
+		return weather.ErrInvalidCoordinates
+	}
+
+	params := url.Values{}
+	params.Add("lat", strconv.FormatFloat(latitude, 'f', -1, 64))
+	params.Add("lon", strconv.FormatFloat(longitude, 'f', -1, 64))
+	params.Add("units", c.config.Units)
+	params.Add("appid", c.config.APIKey)
+
+	url := c.config.BaseURL + path + "?" + params.Encode()
+	return weather.GetJSON(ctx, c.httpClient, url, nil, result)
+}
@@ -0,0 +1,182 @@
+package openweathermap_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"spotify_recommender/internal/domain/valueObject"
+	"spotify_recommender/internal/infrastructure/external/weather"
+	"spotify_recommender/internal/infrastructure/external/weather/openweathermap"
+	"spotify_recommender/internal/infrastructure/external/weather/weathertest"
+	"testing"
+)
+
+func newTestClient(t *testing.T, configure func(*openweathermap.Config)) (*openweathermap.Client, *weathertest.Server) {
+	t.Helper()
+
+	server := weathertest.NewServer(weathertest.DefaultFixtures())
+	t.Cleanup(server.Close)
+
+	config := server.OpenWeatherMapConfig()
+	if configure != nil {
+		configure(&config)
+	}
+	return openweathermap.NewClient(config), server
+}
+
+func fixtureLocation(t *testing.T, name string) weathertest.Location {
+	t.Helper()
+
+	for _, location := range weathertest.DefaultFixtures().Locations {
+		if location.Name == name {
+			return location
+		}
+	}
+	t.Fatalf("no fixture location %q", name)
+	return weathertest.Location{}
+}
+
+func TestClientObservations(t *testing.T) {
+	tests := []struct {
+		location string
+		units    string
+	}{
+		{location: "London", units: "metric"},
+		{location: "Moscow", units: "standard"},
+		{location: "Cairo", units: "imperial"},
+		{location: "Tokyo", units: "metric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location+"/"+tt.units, func(t *testing.T) {
+			client, _ := newTestClient(t, func(config *openweathermap.Config) {
+				config.Units = tt.units
+			})
+			location := fixtureLocation(t, tt.location)
+			want := location.Weather
+
+			observation, err := client.GetCurrentObservation(context.Background(), location.Latitude, location.Longitude)
+			if err != nil {
+				t.Fatalf("GetCurrentObservation() error = %v", err)
+			}
+
+			if observation.ConditionCode != want.ConditionID {
+				t.Errorf("ConditionCode = %d, want %d", observation.ConditionCode, want.ConditionID)
+			}
+			if observation.Units != valueObject.WeatherUnits(tt.units) {
+				t.Errorf("Units = %q, want %q", observation.Units, tt.units)
+			}
+			if got := observation.FeelsLikeCelsius(); math.Abs(got-want.FeelsLike) > 0.01 {
+				t.Errorf("FeelsLikeCelsius() = %v, want %v", got, want.FeelsLike)
+			}
+			if got := observation.WindSpeedMetresPerSecond(); math.Abs(got-want.WindSpeed) > 0.01 {
+				t.Errorf("WindSpeedMetresPerSecond() = %v, want %v", got, want.WindSpeed)
+			}
+			if observation.Humidity != want.Humidity || observation.CloudCover != want.Clouds {
+				t.Errorf("humidity, cloud cover = %d, %d, want %d, %d",
+					observation.Humidity, observation.CloudCover, want.Humidity, want.Clouds)
+			}
+		})
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	london := fixtureLocation(t, "London")
+
+	tests := []struct {
+		name         string
+		configure    func(config *openweathermap.Config)
+		fail         int
+		latitude     float64
+		wantErr      error
+		wantRequests int
+	}{
+		{
+			name:         "rejected api key",
+			configure:    func(config *openweathermap.Config) { config.APIKey = "wrong-key" },
+			wantErr:      weather.ErrUnauthorized,
+			wantRequests: 1,
+		},
+		{
+			name:      "missing api key",
+			configure: func(config *openweathermap.Config) { config.APIKey = "" },
+			wantErr:   weather.ErrNotConfigured,
+		},
+		{
+			name:     "invalid coordinates",
+			latitude: 91,
+			wantErr:  weather.ErrInvalidCoordinates,
+		},
+		{
+			name:         "rate limited",
+			fail:         http.StatusTooManyRequests,
+			wantErr:      weather.ErrRateLimited,
+			wantRequests: 1,
+		},
+		{
+			name:         "not found",
+			fail:         http.StatusNotFound,
+			wantErr:      weather.ErrNotFound,
+			wantRequests: 1,
+		},
+		{
+			name:         "server error",
+			fail:         http.StatusBadGateway,
+			wantErr:      weather.ErrUnavailable,
+			wantRequests: 1,
+		},
+		{
+			name:         "bad request",
+			fail:         http.StatusBadRequest,
+			wantErr:      weather.ErrRequest,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newTestClient(t, tt.configure)
+			if tt.fail != 0 {
+				server.FailNext(weathertest.OpenWeatherMapPath, tt.fail, 1)
+			}
+			latitude := london.Latitude
+			if tt.latitude != 0 {
+				latitude = tt.latitude
+			}
+
+			if _, err := client.GetCurrentWeather(context.Background(), latitude, london.Longitude); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetCurrentWeather() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := server.Requests(weathertest.OpenWeatherMapPath); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestClientHourlyForecast(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	london := fixtureLocation(t, "London")
+
+	forecasts, err := client.GetHourlyForecast(context.Background(), london.Latitude, london.Longitude)
+	if err != nil {
+		t.Fatalf("GetHourlyForecast() error = %v", err)
+	}
+	if len(forecasts) != 40 {
+		t.Fatalf("GetHourlyForecast() returned %d steps, want 40", len(forecasts))
+	}
+
+	// The London fixture clears up a day ahead.
+	if got := forecasts[0].Weather; got != valueObject.WeatherRainy {
+		t.Errorf("first step weather = %q, want %q", got, valueObject.WeatherRainy)
+	}
+	if got := forecasts[len(forecasts)-1].Weather; got != valueObject.WeatherSunny {
+		t.Errorf("last step weather = %q, want %q", got, valueObject.WeatherSunny)
+	}
+	for i := 1; i < len(forecasts); i++ {
+		if !forecasts[i].Time.After(forecasts[i-1].Time) {
+			t.Fatalf("step %d at %s is not after step %d at %s", i, forecasts[i].Time, i-1, forecasts[i-1].Time)
+		}
+	}
+}
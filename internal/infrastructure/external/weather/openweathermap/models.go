@@ -0,0 +1,50 @@
+package openweathermap
+
+import "spotify_recommender/internal/domain/valueObject"
+
+type condition struct {
+	ID          int    `json:"id"`
+	Main        string `json:"main"`
+	Description string `json:"description"`
+}
+
+// currentWeatherResponse is the subset of the current weather response we
+// use. Temperatures and wind speed are in the requested units.
+type currentWeatherResponse struct {
+	Conditions []condition `json:"weather"`
+	Main       struct {
+		Temperature float64 `json:"temp"`
+		FeelsLike   float64 `json:"feels_like"`
+		Humidity    int     `json:"humidity"`
+	} `json:"main"`
+	Wind struct {
+		Speed float64 `json:"speed"`
+	} `json:"wind"`
+	Clouds struct {
+		All int `json:"all"`
+	} `json:"clouds"`
+	Name string `json:"name"`
+}
+
+// forecastResponse is the subset of the 5 day / 3 hour forecast response we
+// use. Each step carries the same fields as a current weather response.
+type forecastResponse struct {
+	List []forecastStep `json:"list"`
+}
+
+type forecastStep struct {
+	currentWeatherResponse
+	Time int64 `json:"dt"`
+}
+
+func (r *currentWeatherResponse) toObservation(units valueObject.WeatherUnits) valueObject.WeatherObservation {
+	return valueObject.WeatherObservation{
+		ConditionCode: r.Conditions[0].ID,
+		Temperature:   r.Main.Temperature,
+		FeelsLike:     r.Main.FeelsLike,
+		WindSpeed:     r.Wind.Speed,
+		Humidity:      r.Main.Humidity,
+		CloudCover:    r.Clouds.All,
+		Units:         units,
+	}
+}
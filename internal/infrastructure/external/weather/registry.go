@@ -0,0 +1,197 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/valueObject"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Provider is a named weather source tried by a Registry. Lookups that take
+// longer than Timeout are abandoned in favor of the next provider.
+type Provider struct {
+	Name    string
+	Service usecase.WeatherService
+	Timeout time.Duration
+}
+
+type HealthConfig struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		FailureThreshold: 3,
+		Cooldown:         time.Minute,
+	}
+}
+
+// Registry implements usecase.WeatherReporter by asking its providers in
+// order until one answers. A provider that fails FailureThreshold times in a
+// row is skipped until Cooldown has passed, then given another chance.
+type Registry struct {
+	config    HealthConfig
+	providers []*providerHealth
+}
+
+type providerHealth struct {
+	Provider
+
+	mutex          sync.Mutex
+	failures       int
+	unhealthyUntil time.Time
+}
+
+func NewRegistry(config HealthConfig, providers ...Provider) *Registry {
+	if config.FailureThreshold <= 0 {
+		config = DefaultHealthConfig()
+	}
+
+	registry := &Registry{config: config}
+	for _, provider := range providers {
+		registry.providers = append(registry.providers, &providerHealth{Provider: provider})
+	}
+	return registry
+}
+
+func (r *Registry) GetCurrentWeather(ctx context.Context, latitude, longitude float64) (valueObject.Weather, error) {
+	report, err := r.ReportCurrentWeather(ctx, latitude, longitude)
+	if err != nil {
+		return "", err
+	}
+	return report.Weather, nil
+}
+
+func (r *Registry) ReportCurrentWeather(
+	ctx context.Context,
+	latitude, longitude float64,
+) (usecase.WeatherReport, error) {
+	var weather valueObject.Weather
+	source, err := r.try(ctx, latitude, longitude, func(ctx context.Context, provider Provider) (bool, error) {
+		var err error
+		weather, err = provider.Service.GetCurrentWeather(ctx, latitude, longitude)
+		if err == nil && !valueObject.ValidWeather(weather) {
+			err = fmt.Errorf("unknown weather %q", weather)
+		}
+		return true, err
+	})
+	if err != nil {
+		return usecase.WeatherReport{}, err
+	}
+	return usecase.WeatherReport{Weather: weather, Source: source}, nil
+}
+
+// ReportHourlyForecast asks the providers that make forecasts, those whose
+// Service is also a usecase.ForecastService.
+func (r *Registry) ReportHourlyForecast(
+	ctx context.Context,
+	latitude, longitude float64,
+) (usecase.ForecastReport, error) {
+	var forecasts []usecase.WeatherForecast
+	source, err := r.try(ctx, latitude, longitude, func(ctx context.Context, provider Provider) (bool, error) {
+		forecaster, ok := provider.Service.(usecase.ForecastService)
+		if !ok {
+			return false, nil
+		}
+
+		var err error
+		forecasts, err = forecaster.GetHourlyForecast(ctx, latitude, longitude)
+		if err == nil && len(forecasts) == 0 {
+			err = errors.New("empty forecast")
+		}
+		return true, err
+	})
+	if err != nil {
+		return usecase.ForecastReport{}, err
+	}
+	return usecase.ForecastReport{Forecasts: forecasts, Source: source}, nil
+}
+
+// try calls lookup with each available provider in turn, within the
+// provider's timeout, until one succeeds, and returns that provider's name.
+// lookup reports false for a provider that cannot make the lookup at all.
+func (r *Registry) try(
+	ctx context.Context,
+	latitude, longitude float64,
+	lookup func(ctx context.Context, provider Provider) (bool, error),
+) (string, error) {
+	if !ValidCoordinates(latitude, longitude) {
+		return "", ErrInvalidCoordinates
+	}
+
+	var errs []error
+	for _, provider := range r.providers {
+		if !provider.available() {
+			continue
+		}
+
+		supported, err := provider.lookup(ctx, lookup)
+		if !supported {
+			continue
+		}
+		if err == nil {
+			provider.success()
+			return provider.Name, nil
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		provider.failure(r.config, err)
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name, err))
+	}
+
+	if len(errs) == 0 {
+		return "", ErrNoProviderAvailable
+	}
+	return "", ErrNoProviderAvailable.Wrap(errors.Join(errs...))
+}
+
+func (p *providerHealth) lookup(
+	ctx context.Context,
+	lookup func(ctx context.Context, provider Provider) (bool, error),
+) (bool, error) {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	return lookup(ctx, p.Provider)
+}
+
+func (p *providerHealth) available() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return !time.Now().Before(p.unhealthyUntil)
+}
+
+func (p *providerHealth) success() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !p.unhealthyUntil.IsZero() {
+		log.Info().Str("provider", p.Name).Msg("weather provider recovered")
+	}
+	p.failures = 0
+	p.unhealthyUntil = time.Time{}
+}
+
+func (p *providerHealth) failure(config HealthConfig, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.failures++
+	if p.failures >= config.FailureThreshold {
+		p.unhealthyUntil = time.Now().Add(config.Cooldown)
+		log.Warn().Err(err).Str("provider", p.Name).Int("failures", p.failures).
+			Dur("cooldown", config.Cooldown).Msg("weather provider marked unhealthy")
+	}
+}
@@ -0,0 +1,238 @@
+package weather_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"spotify_recommender/internal/domain/valueObject"
+	"spotify_recommender/internal/infrastructure/external/weather"
+	"spotify_recommender/internal/infrastructure/external/weather/metno"
+	"spotify_recommender/internal/infrastructure/external/weather/openmeteo"
+	"spotify_recommender/internal/infrastructure/external/weather/openweathermap"
+	"spotify_recommender/internal/infrastructure/external/weather/weathertest"
+	"testing"
+	"time"
+)
+
+const londonLatitude, londonLongitude = 51.5074, -0.1278
+
+func newTestServer(t *testing.T) *weathertest.Server {
+	t.Helper()
+
+	server := weathertest.NewServer(weathertest.DefaultFixtures())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testProviders returns the providers in the order the registry is
+// configured with by default, all talking to server.
+func testProviders(server *weathertest.Server) []weather.Provider {
+	return []weather.Provider{
+		{Name: openweathermap.ProviderName, Service: openweathermap.NewClient(server.OpenWeatherMapConfig())},
+		{Name: openmeteo.ProviderName, Service: openmeteo.NewClient(server.OpenMeteoConfig())},
+		{Name: metno.ProviderName, Service: metno.NewClient(server.MetNoConfig())},
+	}
+}
+
+func TestProvidersClassifyFixtureWeather(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := []struct {
+		location string
+		want     valueObject.Weather
+	}{
+		{location: "London", want: valueObject.WeatherRainy},
+		{location: "Moscow", want: valueObject.WeatherSnowy},
+		{location: "Cairo", want: valueObject.WeatherHot},
+		{location: "Chicago", want: valueObject.WeatherWindy},
+		{location: "Tokyo", want: valueObject.WeatherStormy},
+		{location: "San Francisco", want: valueObject.WeatherFoggy},
+		{location: "Sydney", want: valueObject.WeatherSunny},
+		{location: "Reykjavik", want: valueObject.WeatherCold},
+		{location: "Seattle", want: valueObject.WeatherCloudy},
+	}
+
+	locations := make(map[string]weathertest.Location)
+	for _, location := range weathertest.DefaultFixtures().Locations {
+		locations[location.Name] = location
+	}
+
+	for _, provider := range testProviders(server) {
+		for _, tt := range tests {
+			t.Run(provider.Name+"/"+tt.location, func(t *testing.T) {
+				location, ok := locations[tt.location]
+				if !ok {
+					t.Fatalf("no fixture location %q", tt.location)
+				}
+
+				got, err := provider.Service.GetCurrentWeather(context.Background(), location.Latitude, location.Longitude)
+				if err != nil {
+					t.Fatalf("GetCurrentWeather() error = %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("GetCurrentWeather() = %q, want %q", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestRegistryFallsBack(t *testing.T) {
+	tests := []struct {
+		name       string
+		failing    []string
+		wantSource string
+		wantErr    error
+	}{
+		{
+			name:       "uses the first provider",
+			wantSource: openweathermap.ProviderName,
+		},
+		{
+			name:       "falls back to the second provider",
+			failing:    []string{weathertest.OpenWeatherMapPath},
+			wantSource: openmeteo.ProviderName,
+		},
+		{
+			name:       "falls back to the last provider",
+			failing:    []string{weathertest.OpenWeatherMapPath, weathertest.OpenMeteoPath},
+			wantSource: metno.ProviderName,
+		},
+		{
+			name:    "fails when every provider fails",
+			failing: []string{weathertest.OpenWeatherMapPath, weathertest.OpenMeteoPath, weathertest.MetNoPath},
+			wantErr: weather.ErrNoProviderAvailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			for _, path := range tt.failing {
+				server.FailNext(path, http.StatusServiceUnavailable, 1)
+			}
+			registry := weather.NewRegistry(weather.DefaultHealthConfig(), testProviders(server)...)
+
+			report, err := registry.ReportCurrentWeather(context.Background(), londonLatitude, londonLongitude)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ReportCurrentWeather() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ReportCurrentWeather() error = %v", err)
+			} else if report.Source != tt.wantSource || report.Weather != valueObject.WeatherRainy {
+				t.Fatalf("ReportCurrentWeather() = %+v, want rainy from %s", report, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestRegistrySkipsUnhealthyProviders(t *testing.T) {
+	server := newTestServer(t)
+	registry := weather.NewRegistry(
+		weather.HealthConfig{FailureThreshold: 2, Cooldown: time.Hour},
+		testProviders(server)...,
+	)
+	ctx := context.Background()
+
+	server.FailNext(weathertest.OpenWeatherMapPath, http.StatusInternalServerError, 2)
+	for i := 0; i < 2; i++ {
+		report, err := registry.ReportCurrentWeather(ctx, londonLatitude, londonLongitude)
+		if err != nil {
+			t.Fatalf("ReportCurrentWeather() #%d error = %v", i+1, err)
+		}
+		if report.Source != openmeteo.ProviderName {
+			t.Fatalf("ReportCurrentWeather() #%d source = %q, want %q", i+1, report.Source, openmeteo.ProviderName)
+		}
+	}
+
+	report, err := registry.ReportCurrentWeather(ctx, londonLatitude, londonLongitude)
+	if err != nil {
+		t.Fatalf("ReportCurrentWeather() error = %v", err)
+	}
+	if report.Source != openmeteo.ProviderName {
+		t.Errorf("ReportCurrentWeather() source = %q, want %q", report.Source, openmeteo.ProviderName)
+	}
+	if got := server.Requests(weathertest.OpenWeatherMapPath); got != 2 {
+		t.Errorf("requests to the unhealthy provider = %d, want 2", got)
+	}
+}
+
+func TestRegistryRejectsInvalidCoordinates(t *testing.T) {
+	server := newTestServer(t)
+	registry := weather.NewRegistry(weather.DefaultHealthConfig(), testProviders(server)...)
+
+	if _, err := registry.ReportCurrentWeather(context.Background(), 91, 0); !errors.Is(err, weather.ErrInvalidCoordinates) {
+		t.Fatalf("ReportCurrentWeather() error = %v, want %v", err, weather.ErrInvalidCoordinates)
+	}
+	if got := server.Requests(weathertest.OpenWeatherMapPath); got != 0 {
+		t.Errorf("requests = %d, want 0", got)
+	}
+}
+
+func TestRegistryReportsForecasts(t *testing.T) {
+	tests := []struct {
+		name       string
+		failing    []string
+		wantSource string
+		wantErr    error
+	}{
+		{
+			name:       "uses the first provider",
+			wantSource: openweathermap.ProviderName,
+		},
+		{
+			name:       "falls back to the second provider",
+			failing:    []string{weathertest.OpenWeatherMapForecastPath},
+			wantSource: openmeteo.ProviderName,
+		},
+		{
+			name:       "falls back to the last provider",
+			failing:    []string{weathertest.OpenWeatherMapForecastPath, weathertest.OpenMeteoPath},
+			wantSource: metno.ProviderName,
+		},
+		{
+			name:    "fails when every provider fails",
+			failing: []string{weathertest.OpenWeatherMapForecastPath, weathertest.OpenMeteoPath, weathertest.MetNoPath},
+			wantErr: weather.ErrNoProviderAvailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t)
+			for _, path := range tt.failing {
+				server.FailNext(path, http.StatusServiceUnavailable, 1)
+			}
+			registry := weather.NewRegistry(weather.DefaultHealthConfig(), testProviders(server)...)
+
+			forecast, err := registry.ReportHourlyForecast(context.Background(), londonLatitude, londonLongitude)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ReportHourlyForecast() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReportHourlyForecast() error = %v", err)
+			}
+			if forecast.Source != tt.wantSource {
+				t.Errorf("ReportHourlyForecast() source = %q, want %q", forecast.Source, tt.wantSource)
+			}
+
+			// The London fixture clears up a day ahead.
+			now := time.Now()
+			for _, check := range []struct {
+				at   time.Time
+				want valueObject.Weather
+			}{
+				{at: now.Add(3 * time.Hour), want: valueObject.WeatherRainy},
+				{at: now.Add(30 * time.Hour), want: valueObject.WeatherSunny},
+			} {
+				if got, ok := forecast.WeatherAt(check.at); !ok || got != check.want {
+					t.Errorf("WeatherAt(%s) = %q, %v, want %q", check.at.Sub(now), got, ok, check.want)
+				}
+			}
+		})
+	}
+}
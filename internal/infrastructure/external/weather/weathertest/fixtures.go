@@ -0,0 +1,76 @@
+package weathertest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed fixtures/locations.json
+var defaultLocations []byte
+
+// Observation is the weather at a fixture location, in metric units:
+// degrees Celsius and metres per second.
+type Observation struct {
+	ConditionID int     `json:"condition_id"`
+	Main        string  `json:"main"`
+	Description string  `json:"description"`
+	Temperature float64 `json:"temperature"`
+	FeelsLike   float64 `json:"feels_like"`
+	Humidity    int     `json:"humidity"`
+	WindSpeed   float64 `json:"wind_speed"`
+	Clouds      int     `json:"clouds"`
+}
+
+// Location is a place with its current weather. Forecasts for it repeat the
+// current weather except where Forecast changes it.
+type Location struct {
+	Name      string           `json:"name"`
+	Latitude  float64          `json:"lat"`
+	Longitude float64          `json:"lon"`
+	Weather   Observation      `json:"weather"`
+	Forecast  []ForecastChange `json:"forecast,omitempty"`
+}
+
+// ForecastChange makes the forecast weather at a location Weather from
+// HoursAhead hours after the request on, until the next change.
+type ForecastChange struct {
+	HoursAhead int         `json:"hours_ahead"`
+	Weather    Observation `json:"weather"`
+}
+
+// WeatherIn returns the weather forecast at the location the given number
+// of hours from now.
+func (l Location) WeatherIn(hours float64) Observation {
+	weather := l.Weather
+	for _, change := range l.Forecast {
+		if float64(change.HoursAhead) <= hours {
+			weather = change.Weather
+		}
+	}
+	return weather
+}
+
+// Fixtures is the data a fake server starts with. Requests are answered with
+// the weather of the location nearest to the requested coordinates.
+type Fixtures struct {
+	Locations []Location `json:"locations"`
+}
+
+// DefaultFixtures returns the bundled locations, a handful of cities whose
+// weather covers every condition we classify.
+func DefaultFixtures() Fixtures {
+	fixtures, err := LoadFixtures(defaultLocations)
+	if err != nil {
+		panic(err)
+	}
+	return fixtures
+}
+
+func LoadFixtures(data []byte) (Fixtures, error) {
+	var fixtures Fixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return Fixtures{}, fmt.Errorf("failed to parse weather fixtures: %w", err)
+	}
+	return fixtures, nil
+}
@@ -0,0 +1,572 @@
+// Package weathertest provides an in-process fake of the OpenWeatherMap,
+// Open-Meteo and MET Norway weather APIs, backed by fixture data, for
+// exercising the weather clients and the code built on them without network
+// access. All three report the same weather and forecasts for a location.
+package weathertest
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"spotify_recommender/internal/infrastructure/external/weather/metno"
+	"spotify_recommender/internal/infrastructure/external/weather/openmeteo"
+	"spotify_recommender/internal/infrastructure/external/weather/openweathermap"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// APIKey is the only OpenWeatherMap API key the fake server accepts.
+	APIKey = "fake-weather-api-key"
+
+	// Paths of the fake endpoints, for use with FailNext and Requests.
+	OpenWeatherMapPath         = "/data/2.5/weather"
+	OpenWeatherMapForecastPath = "/data/2.5/forecast"
+	OpenMeteoPath              = "/v1/forecast"
+	MetNoPath                  = "/weatherapi/locationforecast/2.0/compact"
+)
+
+// Forecast lengths, as the real APIs make them.
+const (
+	openWeatherMapForecastSteps = 40
+	openMeteoDefaultDays        = 7
+	openMeteoMaxDays            = 16
+	metNoHourlySteps            = 60
+	metNoForecastHours          = 9 * 24
+)
+
+// fault makes matching requests fail with status or, when status is zero,
+// answer normally after delay.
+type fault struct {
+	pathPrefix string
+	status     int
+	delay      time.Duration
+	remaining  int
+}
+
+type Server struct {
+	server *httptest.Server
+
+	mutex     sync.Mutex
+	locations []Location
+	faults    []*fault
+	requests  map[string]int
+}
+
+func NewServer(fixtures Fixtures) *Server {
+	s := &Server{
+		locations: append([]Location(nil), fixtures.Locations...),
+		requests:  make(map[string]int),
+	}
+
+	s.server = httptest.NewServer(s.routes())
+	return s
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// OpenWeatherMapConfig returns an OpenWeatherMap client configuration that
+// talks to this server.
+func (s *Server) OpenWeatherMapConfig() openweathermap.Config {
+	return openweathermap.Config{
+		APIKey:  APIKey,
+		Units:   openweathermap.DefaultUnits,
+		BaseURL: s.server.URL + "/data/2.5",
+		Timeout: 2 * time.Second,
+	}
+}
+
+// OpenMeteoConfig returns an Open-Meteo client configuration that talks to
+// this server.
+func (s *Server) OpenMeteoConfig() openmeteo.Config {
+	return openmeteo.Config{
+		BaseURL: s.server.URL + "/v1",
+		Timeout: 2 * time.Second,
+	}
+}
+
+// MetNoConfig returns a MET Norway client configuration that talks to this
+// server.
+func (s *Server) MetNoConfig() metno.Config {
+	return metno.Config{
+		BaseURL: s.server.URL + "/weatherapi",
+		Timeout: 2 * time.Second,
+	}
+}
+
+// SetWeather changes the weather at the named location. It reports whether
+// the location exists.
+func (s *Server) SetWeather(name string, observation Observation) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.locations {
+		if s.locations[i].Name == name {
+			s.locations[i].Weather = observation
+			return true
+		}
+	}
+	return false
+}
+
+// SetForecast replaces the forecast changes at the named location. It
+// reports whether the location exists.
+func (s *Server) SetForecast(name string, changes ...ForecastChange) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.locations {
+		if s.locations[i].Name == name {
+			s.locations[i].Forecast = append([]ForecastChange(nil), changes...)
+			return true
+		}
+	}
+	return false
+}
+
+// FailNext makes the next times requests whose path starts with pathPrefix
+// answer with status.
+func (s *Server) FailNext(pathPrefix string, status, times int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = append(s.faults, &fault{pathPrefix: pathPrefix, status: status, remaining: times})
+}
+
+// DelayNext makes the next times requests whose path starts with pathPrefix
+// answer only after delay, for exercising client timeouts.
+func (s *Server) DelayNext(pathPrefix string, delay time.Duration, times int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = append(s.faults, &fault{pathPrefix: pathPrefix, delay: delay, remaining: times})
+}
+
+// Requests returns how many requests were made to path, including failed
+// ones.
+func (s *Server) Requests(path string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.requests[path]
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+OpenWeatherMapPath, s.handleOpenWeatherMap)
+	mux.HandleFunc("GET "+OpenWeatherMapForecastPath, s.handleOpenWeatherMapForecast)
+	mux.HandleFunc("GET "+OpenMeteoPath, s.handleOpenMeteo)
+	mux.HandleFunc("GET "+MetNoPath, s.handleMetNo)
+
+	return s.injectFaults(mux)
+}
+
+func (s *Server) injectFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		s.requests[r.URL.Path]++
+
+		var injected *fault
+		for _, f := range s.faults {
+			if f.remaining > 0 && strings.HasPrefix(r.URL.Path, f.pathPrefix) {
+				f.remaining--
+				injected = f
+				break
+			}
+		}
+		s.mutex.Unlock()
+
+		if injected != nil && injected.status != 0 {
+			writeAPIError(w, injected.status, http.StatusText(injected.status))
+			return
+		}
+		if injected != nil {
+			select {
+			case <-time.After(injected.delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleOpenWeatherMap(w http.ResponseWriter, r *http.Request) {
+	units, ok := s.openWeatherMapUnits(w, r)
+	if !ok {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	location, ok := s.openWeatherMapLocation(w, r)
+	if !ok {
+		return
+	}
+
+	step := openWeatherMapStep(location.Weather, units)
+	step["coord"] = map[string]interface{}{"lat": location.Latitude, "lon": location.Longitude}
+	step["dt"] = time.Now().Unix()
+	step["name"] = location.Name
+	step["cod"] = http.StatusOK
+	writeJSON(w, http.StatusOK, step)
+}
+
+func (s *Server) handleOpenWeatherMapForecast(w http.ResponseWriter, r *http.Request) {
+	units, ok := s.openWeatherMapUnits(w, r)
+	if !ok {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	location, ok := s.openWeatherMapLocation(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	start := now.UTC().Truncate(3 * time.Hour)
+	steps := make([]interface{}, openWeatherMapForecastSteps)
+	for i := range steps {
+		at := start.Add(time.Duration(i) * 3 * time.Hour)
+		step := openWeatherMapStep(location.WeatherIn(at.Sub(now).Hours()), units)
+		step["dt"] = at.Unix()
+		step["dt_txt"] = at.Format(time.DateTime)
+		steps[i] = step
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"cod":  "200",
+		"cnt":  len(steps),
+		"list": steps,
+		"city": map[string]interface{}{
+			"name":  location.Name,
+			"coord": map[string]interface{}{"lat": location.Latitude, "lon": location.Longitude},
+		},
+	})
+}
+
+// openWeatherMapUnits checks the API key and returns the requested units,
+// answering with an error when either is wrong.
+func (s *Server) openWeatherMapUnits(w http.ResponseWriter, r *http.Request) (string, bool) {
+	query := r.URL.Query()
+	if query.Get("appid") != APIKey {
+		writeAPIError(w, http.StatusUnauthorized,
+			"Invalid API key. Please see https://openweathermap.org/faq#error401 for more info.")
+		return "", false
+	}
+
+	units := query.Get("units")
+	switch units {
+	case "":
+		return "standard", true
+	case "standard", "metric", "imperial":
+		return units, true
+	default:
+		writeAPIError(w, http.StatusBadRequest, "wrong units")
+		return "", false
+	}
+}
+
+// openWeatherMapLocation returns the location nearest to the requested
+// coordinates, answering with an error when there is none. The caller must
+// hold the mutex.
+func (s *Server) openWeatherMapLocation(w http.ResponseWriter, r *http.Request) (Location, bool) {
+	query := r.URL.Query()
+	latitude, err := strconv.ParseFloat(query.Get("lat"), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		writeAPIError(w, http.StatusBadRequest, "wrong latitude")
+		return Location{}, false
+	}
+	longitude, err := strconv.ParseFloat(query.Get("lon"), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		writeAPIError(w, http.StatusBadRequest, "wrong longitude")
+		return Location{}, false
+	}
+
+	location, ok := s.nearest(latitude, longitude)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "city not found")
+		return Location{}, false
+	}
+	return location, true
+}
+
+// openWeatherMapStep returns the fields current weather and forecast steps
+// share.
+func openWeatherMapStep(observation Observation, units string) map[string]interface{} {
+	return map[string]interface{}{
+		"weather": []interface{}{map[string]interface{}{
+			"id":          observation.ConditionID,
+			"main":        observation.Main,
+			"description": observation.Description,
+		}},
+		"main": map[string]interface{}{
+			"temp":       convertTemperature(observation.Temperature, units),
+			"feels_like": convertTemperature(observation.FeelsLike, units),
+			"humidity":   observation.Humidity,
+		},
+		"wind":   map[string]interface{}{"speed": convertSpeed(observation.WindSpeed, units)},
+		"clouds": map[string]interface{}{"all": observation.Clouds},
+	}
+}
+
+func (s *Server) handleOpenMeteo(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	latitude, err := strconv.ParseFloat(query.Get("latitude"), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		writeOpenMeteoError(w, "Latitude must be in range of -90 to 90°.")
+		return
+	}
+	longitude, err := strconv.ParseFloat(query.Get("longitude"), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		writeOpenMeteoError(w, "Longitude must be in range of -180 to 180°.")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	location, ok := s.nearest(latitude, longitude)
+	if !ok {
+		writeOpenMeteoError(w, "No data is available for this location")
+		return
+	}
+
+	days := openMeteoDefaultDays
+	if query.Has("forecast_days") {
+		days, err = strconv.Atoi(query.Get("forecast_days"))
+		if err != nil || days < 0 || days > openMeteoMaxDays {
+			writeOpenMeteoError(w, "Forecast days is invalid. Allowed range 0 to 16.")
+			return
+		}
+	}
+
+	convertWindSpeed := func(metresPerSecond float64) float64 {
+		if query.Get("wind_speed_unit") == "ms" {
+			return metresPerSecond
+		}
+		return round(metresPerSecond * 3.6)
+	}
+	formatTime := func(t time.Time) interface{} {
+		if query.Get("timeformat") == "unixtime" {
+			return t.Unix()
+		}
+		return t.UTC().Format("2006-01-02T15:04")
+	}
+
+	now := time.Now()
+	observation := location.Weather
+	response := map[string]interface{}{
+		"latitude":  location.Latitude,
+		"longitude": location.Longitude,
+	}
+	if query.Has("current") {
+		response["current"] = map[string]interface{}{
+			"time":                 formatTime(now),
+			"temperature_2m":       observation.Temperature,
+			"apparent_temperature": observation.FeelsLike,
+			"relative_humidity_2m": observation.Humidity,
+			"cloud_cover":          observation.Clouds,
+			"wind_speed_10m":       convertWindSpeed(observation.WindSpeed),
+			"weather_code":         wmoCode(observation.ConditionID),
+		}
+	}
+	if query.Has("hourly") {
+		hourly := map[string][]interface{}{}
+		start := now.UTC().Truncate(24 * time.Hour)
+		for i := 0; i < days*24; i++ {
+			at := start.Add(time.Duration(i) * time.Hour)
+			forecast := location.WeatherIn(at.Sub(now).Hours())
+			hourly["time"] = append(hourly["time"], formatTime(at))
+			hourly["temperature_2m"] = append(hourly["temperature_2m"], forecast.Temperature)
+			hourly["apparent_temperature"] = append(hourly["apparent_temperature"], forecast.FeelsLike)
+			hourly["relative_humidity_2m"] = append(hourly["relative_humidity_2m"], forecast.Humidity)
+			hourly["cloud_cover"] = append(hourly["cloud_cover"], forecast.Clouds)
+			hourly["wind_speed_10m"] = append(hourly["wind_speed_10m"], convertWindSpeed(forecast.WindSpeed))
+			hourly["weather_code"] = append(hourly["weather_code"], wmoCode(forecast.ConditionID))
+		}
+		response["hourly"] = hourly
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleMetNo(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("User-Agent") == "" {
+		writeJSON(w, http.StatusForbidden, map[string]interface{}{"message": "missing User-Agent"})
+		return
+	}
+
+	query := r.URL.Query()
+	latitude, latErr := strconv.ParseFloat(query.Get("lat"), 64)
+	longitude, lonErr := strconv.ParseFloat(query.Get("lon"), 64)
+	if latErr != nil || lonErr != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"message": "invalid coordinates"})
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	location, ok := s.nearest(latitude, longitude)
+	if !ok {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{"message": "no data for location"})
+		return
+	}
+
+	// Steps are hourly at first and six-hourly after, each summarizing the
+	// period it starts.
+	now := time.Now()
+	start := now.UTC().Truncate(time.Hour)
+	var timeseries []interface{}
+	for hour := 0; hour < metNoForecastHours; hour++ {
+		period := "next_1_hours"
+		if hour >= metNoHourlySteps {
+			if hour%6 != 0 {
+				continue
+			}
+			period = "next_6_hours"
+		}
+
+		at := start.Add(time.Duration(hour) * time.Hour)
+		forecast := location.WeatherIn(at.Sub(now).Hours())
+		timeseries = append(timeseries, map[string]interface{}{
+			"time": at.Format(time.RFC3339),
+			"data": map[string]interface{}{
+				"instant": map[string]interface{}{
+					"details": map[string]interface{}{
+						"air_temperature":     forecast.Temperature,
+						"cloud_area_fraction": forecast.Clouds,
+						"relative_humidity":   forecast.Humidity,
+						"wind_speed":          forecast.WindSpeed,
+					},
+				},
+				period: map[string]interface{}{
+					"summary": map[string]interface{}{"symbol_code": metNoSymbol(forecast.ConditionID)},
+				},
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"type":       "Feature",
+		"properties": map[string]interface{}{"timeseries": timeseries},
+	})
+}
+
+// nearest returns the fixture location closest to the coordinates.
+func (s *Server) nearest(latitude, longitude float64) (Location, bool) {
+	var best Location
+	bestDistance := math.Inf(1)
+	for _, location := range s.locations {
+		distance := math.Hypot(location.Latitude-latitude, location.Longitude-longitude)
+		if distance < bestDistance {
+			best, bestDistance = location, distance
+		}
+	}
+	return best, len(s.locations) > 0
+}
+
+// wmoCode translates an OpenWeatherMap condition code to the WMO code
+// Open-Meteo would report.
+func wmoCode(conditionID int) int {
+	switch {
+	case conditionID >= 200 && conditionID < 300:
+		return 95
+	case conditionID >= 300 && conditionID < 400:
+		return 53
+	case conditionID >= 500 && conditionID < 600:
+		return 63
+	case conditionID >= 600 && conditionID < 700:
+		return 73
+	case conditionID >= 700 && conditionID < 800:
+		return 45
+	case conditionID == 801:
+		return 1
+	case conditionID == 802:
+		return 2
+	case conditionID > 802:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// metNoSymbol translates an OpenWeatherMap condition code to the symbol MET
+// Norway would report.
+func metNoSymbol(conditionID int) string {
+	switch {
+	case conditionID >= 200 && conditionID < 300:
+		return "rainandthunder"
+	case conditionID >= 300 && conditionID < 400:
+		return "lightrain"
+	case conditionID >= 500 && conditionID < 600:
+		return "rain"
+	case conditionID >= 600 && conditionID < 700:
+		return "snow"
+	case conditionID >= 700 && conditionID < 800:
+		return "fog"
+	case conditionID == 801:
+		return "fair_day"
+	case conditionID == 802:
+		return "partlycloudy_day"
+	case conditionID > 802:
+		return "cloudy"
+	default:
+		return "clearsky_day"
+	}
+}
+
+func convertTemperature(celsius float64, units string) float64 {
+	switch units {
+	case "imperial":
+		return round(celsius*9/5 + 32)
+	case "standard":
+		return round(celsius + 273.15)
+	default:
+		return celsius
+	}
+}
+
+func convertSpeed(metresPerSecond float64, units string) float64 {
+	if units == "imperial" {
+		return round(metresPerSecond * 2.23694)
+	}
+	return metresPerSecond
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+func writeOpenMeteoError(w http.ResponseWriter, reason string) {
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{"error": true, "reason": reason})
+}
+
+// writeAPIError writes an error the way OpenWeatherMap does, with the status
+// repeated in the body as "cod".
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"cod":     strconv.Itoa(status),
+		"message": message,
+	})
+}
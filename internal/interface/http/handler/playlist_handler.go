@@ -1 +1,284 @@
 package handler
+
+import (
+	"fmt"
+	"net/http"
+	"spotify_recommender/internal/app/dto"
+	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/domainerr"
+	"spotify_recommender/internal/domain/service"
+	"spotify_recommender/internal/domain/valueObject"
+	"strconv"
+)
+
+const (
+	defaultPublicPlaylistsLimit = 20
+	maxPublicPlaylistsLimit     = 100
+)
+
+type PlaylistHandler struct {
+	savePlaylist                   *usecase.SavePlaylistUseCase
+	savePlaylistFromRecommendation *usecase.SavePlaylistFromRecommendationUseCase
+	playlistService                *service.PlaylistService
+}
+
+func NewPlaylistHandler(
+	savePlaylist *usecase.SavePlaylistUseCase,
+	savePlaylistFromRecommendation *usecase.SavePlaylistFromRecommendationUseCase,
+	playlistService *service.PlaylistService,
+) *PlaylistHandler {
+	return &PlaylistHandler{
+		savePlaylist:                   savePlaylist,
+		savePlaylistFromRecommendation: savePlaylistFromRecommendation,
+		playlistService:                playlistService,
+	}
+}
+
+func (h *PlaylistHandler) CreatePlaylist(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var createDTO dto.CreatePlaylistDTO
+	if err := decodeJSON(w, r, &createDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlist, err := h.savePlaylist.Execute(r.Context(), userID, createDTO)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, playlist)
+}
+
+func (h *PlaylistHandler) CreateFromRecommendation(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var createDTO dto.CreatePlaylistFromRecommendationDTO
+	if err := decodeJSON(w, r, &createDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlist, err := h.savePlaylistFromRecommendation.Execute(
+		r.Context(),
+		userID,
+		createDTO.RecommendationID,
+		createDTO.Name,
+		createDTO.Description,
+	)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, playlist)
+}
+
+func (h *PlaylistHandler) GetUserPlaylists(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlists, tracks, err := h.playlistService.GetUserPlaylists(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.PlaylistsFromEntities(playlists, tracks))
+}
+
+func (h *PlaylistHandler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlist, tracks, err := h.playlistService.GetPlaylist(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.PlaylistFromEntity(playlist, tracks))
+}
+
+func (h *PlaylistHandler) UpdatePlaylist(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var updateDTO dto.UpdatePlaylistDTO
+	if err := decodeJSON(w, r, &updateDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlist, err := h.playlistService.UpdatePlaylist(
+		r.Context(),
+		userID,
+		r.PathValue("id"),
+		updateDTO.Name,
+		updateDTO.Description,
+		valueObject.Mood(updateDTO.Mood),
+		updateDTO.IsPublic,
+	)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writePlaylist(w, r, userID, playlist.ID)
+}
+
+func (h *PlaylistHandler) DeletePlaylist(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.playlistService.DeletePlaylist(r.Context(), userID, r.PathValue("id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PlaylistHandler) GetPlaylistTracks(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tracks, err := h.playlistService.GetPlaylistTracks(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.TracksFromEntities(tracks))
+}
+
+func (h *PlaylistHandler) AddTrack(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var addDTO dto.AddTrackDTO
+	if err := decodeJSON(w, r, &addDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlistID := r.PathValue("id")
+	if err := h.playlistService.AddTrackToPlaylist(r.Context(), userID, playlistID, addDTO.TrackID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writePlaylist(w, r, userID, playlistID)
+}
+
+func (h *PlaylistHandler) RemoveTrack(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlistID := r.PathValue("id")
+	if err := h.playlistService.RemoveTrackFromPlaylist(r.Context(), userID, playlistID, r.PathValue("trackID")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writePlaylist(w, r, userID, playlistID)
+}
+
+func (h *PlaylistHandler) GetPublicPlaylists(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := parseIntParam(query.Get("limit"), defaultPublicPlaylistsLimit)
+	if err != nil || limit <= 0 || limit > maxPublicPlaylistsLimit {
+		writeError(w, r, errInvalidPagination.WithFields(domainerr.FieldError{
+			Field:   "limit",
+			Message: fmt.Sprintf("must be between 1 and %d", maxPublicPlaylistsLimit),
+		}))
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, r, errInvalidPagination.WithFields(domainerr.FieldError{
+			Field:   "offset",
+			Message: "must be a non-negative integer",
+		}))
+		return
+	}
+
+	playlists, tracks, err := h.playlistService.GetPublicPlaylists(
+		r.Context(),
+		valueObject.Mood(query.Get("mood")),
+		query.Get("name"),
+		limit,
+		offset,
+	)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.PlaylistsFromEntities(playlists, tracks))
+}
+
+func (h *PlaylistHandler) ExportToSpotify(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playlist, err := h.playlistService.ExportPlaylistToSpotify(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.writePlaylist(w, r, userID, playlist.ID)
+}
+
+func (h *PlaylistHandler) writePlaylist(w http.ResponseWriter, r *http.Request, userID, playlistID string) {
+	playlist, tracks, err := h.playlistService.GetPlaylist(r.Context(), userID, playlistID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.PlaylistFromEntity(playlist, tracks))
+}
+
+func parseIntParam(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -1 +1,168 @@
 package handler
+
+import (
+	"net/http"
+	"spotify_recommender/internal/app/dto"
+	"spotify_recommender/internal/app/usecase"
+	"spotify_recommender/internal/domain/domainerr"
+	"spotify_recommender/internal/domain/service"
+	"strconv"
+)
+
+type RecommendationHandler struct {
+	getRecommendations    *usecase.GetRecommendations
+	recommendationService *service.RecommendationService
+}
+
+func NewRecommendationHandler(
+	getRecommendations *usecase.GetRecommendations,
+	recommendationService *service.RecommendationService,
+) *RecommendationHandler {
+	return &RecommendationHandler{
+		getRecommendations:    getRecommendations,
+		recommendationService: recommendationService,
+	}
+}
+
+func (h *RecommendationHandler) CreateRecommendation(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var requestDTO dto.RecommendationRequestDTO
+	if err := decodeJSON(w, r, &requestDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if requestDTO.Location == nil {
+		requestDTO.Location, err = parseLocation(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	if err := validateLocation(requestDTO.Location); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	recommendation, err := h.getRecommendations.Execute(r.Context(), userID, requestDTO)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recommendation)
+}
+
+func (h *RecommendationHandler) GetRecommendation(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	recommendation, err := h.getRecommendations.GetByID(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recommendation)
+}
+
+func (h *RecommendationHandler) GetRecommendationHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	recommendations, err := h.getRecommendations.GetHistory(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recommendations)
+}
+
+func (h *RecommendationHandler) SubmitTrackFeedback(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var feedbackDTO dto.TrackFeedbackDTO
+	if err := decodeJSON(w, r, &feedbackDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = h.recommendationService.SaveRecommendationFeedback(
+		r.Context(),
+		userID,
+		r.PathValue("id"),
+		r.PathValue("trackID"),
+		*feedbackDTO.Liked,
+	)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseLocation reads a location from the lat and lon query parameters. It
+// returns nil when neither is given.
+func parseLocation(r *http.Request) (*dto.LocationDTO, error) {
+	query := r.URL.Query()
+	if query.Get("lat") == "" && query.Get("lon") == "" {
+		return nil, nil
+	}
+
+	lat, err := strconv.ParseFloat(query.Get("lat"), 64)
+	if err != nil {
+		return nil, errInvalidLocation.WithFields(domainerr.FieldError{
+			Field:   "lat",
+			Message: "must be a number between -90 and 90",
+		})
+	}
+
+	lon, err := strconv.ParseFloat(query.Get("lon"), 64)
+	if err != nil {
+		return nil, errInvalidLocation.WithFields(domainerr.FieldError{
+			Field:   "lon",
+			Message: "must be a number between -180 and 180",
+		})
+	}
+
+	return &dto.LocationDTO{Latitude: lat, Longitude: lon}, nil
+}
+
+func validateLocation(location *dto.LocationDTO) error {
+	if location == nil {
+		return nil
+	}
+
+	if location.Latitude < -90 || location.Latitude > 90 {
+		return errInvalidLocation.WithFields(domainerr.FieldError{
+			Field:   "lat",
+			Message: "must be a number between -90 and 90",
+		})
+	}
+
+	if location.Longitude < -180 || location.Longitude > 180 {
+		return errInvalidLocation.WithFields(domainerr.FieldError{
+			Field:   "lon",
+			Message: "must be a number between -180 and 180",
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"spotify_recommender/internal/domain/domainerr"
+	"spotify_recommender/internal/interface/http/middleware"
+	"spotify_recommender/internal/interface/http/problem"
+)
+
+const maxRequestBodySize = 1 << 20
+
+var (
+	errUnauthenticated   = domainerr.Unauthorized("unauthenticated", "authentication required")
+	errInvalidBody       = domainerr.Validation("invalid_request_body", "request body is not valid JSON")
+	errValidationFailed  = domainerr.Validation("validation_failed", "request validation failed")
+	errInvalidPagination = domainerr.Validation("invalid_pagination", "invalid pagination parameters")
+	errInvalidLocation   = domainerr.Validation("invalid_location", "invalid location parameters")
+)
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if payload != nil {
+		_ = json.NewEncoder(w).Encode(payload)
+	}
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	problem.Write(w, r, err)
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+	return validateStruct(dst)
+}
+
+// decodeOptionalJSON is decodeJSON for endpoints whose body may be left out
+// altogether, in which case dst keeps its zero value.
+func decodeOptionalJSON(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	err := decodeJSON(w, r, dst)
+	if errors.Is(err, io.EOF) {
+		return validateStruct(dst)
+	}
+	return err
+}
+
+func currentUserID(r *http.Request) (string, error) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		return "", errUnauthenticated
+	}
+	return userID, nil
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"net/http"
+	"spotify_recommender/internal/app/dto"
+	"spotify_recommender/internal/app/usecase"
+)
+
+type SpotifyHandler struct {
+	spotifyAccount *usecase.SpotifyAccountUseCase
+	importLibrary  *usecase.ImportSpotifyLibraryUseCase
+	playback       *usecase.PlaybackUseCase
+}
+
+func NewSpotifyHandler(
+	spotifyAccount *usecase.SpotifyAccountUseCase,
+	importLibrary *usecase.ImportSpotifyLibraryUseCase,
+	playback *usecase.PlaybackUseCase,
+) *SpotifyHandler {
+	return &SpotifyHandler{
+		spotifyAccount: spotifyAccount,
+		importLibrary:  importLibrary,
+		playback:       playback,
+	}
+}
+
+type spotifyAuthURLResponse struct {
+	AuthURL string `json:"auth_url"`
+}
+
+func (h *SpotifyHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	authURL, err := h.spotifyAccount.BeginLink(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, spotifyAuthURLResponse{AuthURL: authURL})
+}
+
+// Callback is the OAuth redirect target. It is reached from the user's
+// browser without our bearer token, so the user is identified by the state.
+func (h *SpotifyHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	err := h.spotifyAccount.CompleteLink(r.Context(), query.Get("state"), query.Get("code"), query.Get("error"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"connected": true})
+}
+
+func (h *SpotifyHandler) Disconnect(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.spotifyAccount.Unlink(r.Context(), userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SpotifyHandler) StartLibraryImport(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	libraryImport, err := h.importLibrary.Start(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, dto.LibraryImportFromEntity(libraryImport))
+}
+
+func (h *SpotifyHandler) GetLibraryImport(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	libraryImport, err := h.importLibrary.Status(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.LibraryImportFromEntity(libraryImport))
+}
+
+func (h *SpotifyHandler) GetDevices(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	devices, err := h.playback.Devices(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, devices)
+}
+
+func (h *SpotifyHandler) GetPlayback(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	playback, err := h.playback.CurrentlyPlaying(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, playback)
+}
+
+func (h *SpotifyHandler) QueueTracks(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var queueDTO dto.QueueTracksDTO
+	if err := decodeJSON(w, r, &queueDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.playback.QueueTracks(r.Context(), userID, queueDTO.DeviceID, queueDTO.TrackIDs); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SpotifyHandler) PlayRecommendation(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var playDTO dto.PlayRecommendationDTO
+	if err := decodeOptionalJSON(w, r, &playDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = h.playback.PlayRecommendation(r.Context(), userID, r.PathValue("id"), playDTO.DeviceID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
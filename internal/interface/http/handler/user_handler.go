@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"net/http"
+	"spotify_recommender/internal/app/dto"
 	"spotify_recommender/internal/app/usecase"
 	"spotify_recommender/internal/interface/http/middleware"
 )
@@ -9,3 +11,186 @@ type AuthHandler struct {
 	userUseCase   *usecase.UserManagementUseCase
 	jwtMiddleware *middleware.JWTMiddleware
 }
+
+func NewUserHandler(
+	userUseCase *usecase.UserManagementUseCase,
+	jwtMiddleware *middleware.JWTMiddleware,
+) *AuthHandler {
+	return &AuthHandler{
+		userUseCase:   userUseCase,
+		jwtMiddleware: jwtMiddleware,
+	}
+}
+
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var createDTO dto.CreateUserDTO
+	if err := decodeJSON(w, r, &createDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.userUseCase.Register(r.Context(), createDTO)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, user)
+}
+
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var loginDTO dto.LoginDTO
+	if err := decodeJSON(w, r, &loginDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.userUseCase.Login(r.Context(), loginDTO.Email, loginDTO.Password)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tokens, err := h.jwtMiddleware.IssueTokens(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, authResponse(tokens, user))
+}
+
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var refreshDTO dto.RefreshTokenDTO
+	if err := decodeJSON(w, r, &refreshDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tokens, err := h.jwtMiddleware.RefreshTokens(r.Context(), refreshDTO.RefreshToken)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, authResponse(tokens, nil))
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := middleware.SessionIDFromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+
+	if err := h.jwtMiddleware.RevokeSession(r.Context(), sessionID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var changeDTO dto.ChangePasswordDTO
+	if err := decodeJSON(w, r, &changeDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.userUseCase.ChangePassword(r.Context(), userID, changeDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Every existing session, including the current one, is invalidated;
+	// the caller continues on a freshly issued pair.
+	if err := h.jwtMiddleware.RevokeUserSessions(r.Context(), userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tokens, err := h.jwtMiddleware.IssueTokens(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, authResponse(tokens, nil))
+}
+
+func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.userUseCase.GetUserProfile(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (h *AuthHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var preferencesDTO dto.UpdatePreferencesDTO
+	if err := decodeJSON(w, r, &preferencesDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.userUseCase.UpdatePreferences(r.Context(), userID, preferencesDTO)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (h *AuthHandler) UpdateTimezone(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var timezoneDTO dto.UpdateTimezoneDTO
+	if err := decodeJSON(w, r, &timezoneDTO); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.userUseCase.UpdateTimezone(r.Context(), userID, timezoneDTO)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func authResponse(tokens *middleware.TokenPair, user *dto.UserDTO) dto.AuthResponseDTO {
+	return dto.AuthResponseDTO{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+		User:         user,
+	}
+}
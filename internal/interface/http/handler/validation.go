@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"spotify_recommender/internal/domain/domainerr"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+// newValidator enforces the `binding` tags declared on the DTOs.
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+func validateStruct(dst interface{}) error {
+	err := validate.Struct(dst)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	structType := reflect.Indirect(reflect.ValueOf(dst)).Type()
+
+	fields := make([]domainerr.FieldError, len(validationErrors))
+	for i, fieldErr := range validationErrors {
+		fields[i] = domainerr.FieldError{
+			Field:   fieldErr.Field(),
+			Message: validationMessage(structType, fieldErr),
+		}
+	}
+	return errValidationFailed.WithFields(fields...)
+}
+
+func validationMessage(structType reflect.Type, fieldErr validator.FieldError) string {
+	field := fieldErr.Field()
+
+	switch fieldErr.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "min":
+		if fieldErr.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at least %s characters long", field, fieldErr.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s", field, fieldErr.Param())
+	case "max":
+		if fieldErr.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at most %s characters long", field, fieldErr.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s", field, fieldErr.Param())
+	case "gte":
+		return fmt.Sprintf("%s must be greater than or equal to %s", field, fieldErr.Param())
+	case "lte":
+		return fmt.Sprintf("%s must be less than or equal to %s", field, fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", field, strings.ReplaceAll(fieldErr.Param(), " ", ", "))
+	case "timezone":
+		return fmt.Sprintf("%s must be an IANA time zone name such as Europe/Berlin", field)
+	case "gtefield":
+		return fmt.Sprintf("%s must be greater than or equal to %s", field, jsonFieldName(structType, fieldErr.Param()))
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
+
+func jsonFieldName(structType reflect.Type, fieldName string) string {
+	if structType.Kind() != reflect.Struct {
+		return fieldName
+	}
+	field, ok := structType.FieldByName(fieldName)
+	if !ok {
+		return fieldName
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return fieldName
+	}
+	return name
+}
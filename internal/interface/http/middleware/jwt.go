@@ -1 +1,220 @@
 package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"spotify_recommender/internal/domain/domainerr"
+	"spotify_recommender/internal/domain/entity"
+	"spotify_recommender/internal/domain/repository"
+	"spotify_recommender/internal/interface/http/problem"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMissingToken        = domainerr.Unauthorized("missing_token", "missing bearer token")
+	ErrInvalidToken        = domainerr.Unauthorized("invalid_token", "invalid or expired token")
+	ErrInvalidRefreshToken = domainerr.Unauthorized("invalid_refresh_token", "invalid or expired refresh token")
+)
+
+type contextKey string
+
+const (
+	userIDKey    contextKey = "user_id"
+	sessionIDKey contextKey = "session_id"
+)
+
+type JWTConfig struct {
+	SecretKey            string
+	TokenDuration        time.Duration
+	RefreshTokenDuration time.Duration
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+type accessClaims struct {
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+type JWTMiddleware struct {
+	config      JWTConfig
+	sessionRepo repository.SessionRepository
+}
+
+func NewJWTMiddleware(config JWTConfig, sessionRepo repository.SessionRepository) *JWTMiddleware {
+	return &JWTMiddleware{
+		config:      config,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// IssueTokens starts a new session for the user and returns its first token pair.
+func (m *JWTMiddleware) IssueTokens(ctx context.Context, userID string) (*TokenPair, error) {
+	session := entity.NewSession(userID, m.config.RefreshTokenDuration)
+	if err := m.sessionRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return m.issueForSession(ctx, session)
+}
+
+// RefreshTokens rotates a refresh token. Presenting a token that was already
+// rotated revokes the whole session, since it means the token was leaked.
+func (m *JWTMiddleware) RefreshTokens(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	token, err := m.sessionRepo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil || token.IsExpired() {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := m.sessionRepo.GetSession(ctx, token.SessionID)
+	if err != nil || !session.IsActive() {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	fresh, err := m.sessionRepo.MarkRefreshTokenUsed(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		if err := m.sessionRepo.RevokeSession(ctx, session.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return m.issueForSession(ctx, session)
+}
+
+func (m *JWTMiddleware) RevokeSession(ctx context.Context, sessionID string) error {
+	return m.sessionRepo.RevokeSession(ctx, sessionID)
+}
+
+func (m *JWTMiddleware) RevokeUserSessions(ctx context.Context, userID string) error {
+	return m.sessionRepo.RevokeUserSessions(ctx, userID)
+}
+
+// ValidateToken returns the user and session IDs carried by a valid access token.
+func (m *JWTMiddleware) ValidateToken(ctx context.Context, tokenString string) (string, string, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(m.config.SecretKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || claims.Subject == "" || claims.SessionID == "" {
+		return "", "", ErrInvalidToken
+	}
+
+	session, err := m.sessionRepo.GetSession(ctx, claims.SessionID)
+	if err != nil || !session.IsActive() || session.UserID != claims.Subject {
+		return "", "", ErrInvalidToken
+	}
+
+	return claims.Subject, claims.SessionID, nil
+}
+
+func (m *JWTMiddleware) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString, ok := bearerToken(r)
+		if !ok {
+			problem.Write(w, r, ErrMissingToken)
+			return
+		}
+
+		userID, sessionID, err := m.ValidateToken(r.Context(), tokenString)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDKey, userID)
+		ctx = context.WithValue(ctx, sessionIDKey, sessionID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey).(string)
+	return userID, ok && userID != ""
+}
+
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value(sessionIDKey).(string)
+	return sessionID, ok && sessionID != ""
+}
+
+func (m *JWTMiddleware) issueForSession(ctx context.Context, session *entity.Session) (*TokenPair, error) {
+	accessToken, err := m.signAccessToken(session)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.sessionRepo.SaveRefreshToken(ctx, entity.NewRefreshToken(session, hashToken(refreshToken))); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    m.config.TokenDuration,
+	}, nil
+}
+
+func (m *JWTMiddleware) signAccessToken(session *entity.Session) (string, error) {
+	now := time.Now()
+	claims := accessClaims{
+		SessionID: session.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   session.UserID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.config.TokenDuration)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(m.config.SecretKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return signed, nil
+}
+
+func generateRefreshToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || token == "" {
+		return "", false
+	}
+	return token, true
+}
@@ -0,0 +1,87 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"spotify_recommender/internal/domain/domainerr"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ContentType = "application/problem+json"
+	typePrefix  = "/problems/"
+
+	codeInternal = "internal_error"
+)
+
+// Details is an RFC 7807 problem document. Code is a stable extension
+// member clients can switch on; Errors lists field-level validation failures.
+type Details struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Code     string                 `json:"code"`
+	Errors   []domainerr.FieldError `json:"errors,omitempty"`
+}
+
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	details := FromError(err)
+	details.Instance = r.URL.Path
+
+	if details.Status >= http.StatusInternalServerError {
+		log.Error().Err(err).
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Msg("request failed")
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(details.Status)
+	_ = json.NewEncoder(w).Encode(details)
+}
+
+func FromError(err error) Details {
+	domainErr, ok := domainerr.From(err)
+	if !ok {
+		return newDetails(http.StatusInternalServerError, codeInternal, "internal server error", nil)
+	}
+
+	status := StatusFor(domainErr.Kind)
+	detail := domainErr.Message
+	if status >= http.StatusInternalServerError && domainErr.Kind != domainerr.KindUnavailable {
+		detail = "internal server error"
+	}
+
+	return newDetails(status, domainErr.Code, detail, domainErr.Fields)
+}
+
+func StatusFor(kind domainerr.Kind) int {
+	switch kind {
+	case domainerr.KindNotFound:
+		return http.StatusNotFound
+	case domainerr.KindValidation:
+		return http.StatusBadRequest
+	case domainerr.KindConflict:
+		return http.StatusConflict
+	case domainerr.KindUnauthorized:
+		return http.StatusUnauthorized
+	case domainerr.KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func newDetails(status int, code, detail string, fields []domainerr.FieldError) Details {
+	return Details{
+		Type:   typePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Errors: fields,
+	}
+}
@@ -1 +1,79 @@
 package router
+
+import (
+	"net/http"
+	"spotify_recommender/internal/interface/http/handler"
+	"spotify_recommender/internal/interface/http/middleware"
+	"strings"
+)
+
+const apiPrefix = "/api/v1"
+
+func Setup(
+	userHandler *handler.AuthHandler,
+	recommendationHandler *handler.RecommendationHandler,
+	playlistHandler *handler.PlaylistHandler,
+	spotifyHandler *handler.SpotifyHandler,
+	jwtMiddleware *middleware.JWTMiddleware,
+) http.Handler {
+	mux := http.NewServeMux()
+
+	public := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(route(pattern), h)
+	}
+	protected := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(route(pattern), jwtMiddleware.Authenticate(h))
+	}
+
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	public("POST /auth/register", userHandler.Register)
+	public("POST /auth/login", userHandler.Login)
+	public("POST /auth/refresh", userHandler.Refresh)
+	protected("POST /auth/logout", userHandler.Logout)
+
+	protected("GET /auth/spotify/login", spotifyHandler.Connect)
+	public("GET /auth/spotify/callback", spotifyHandler.Callback)
+	protected("DELETE /auth/spotify", spotifyHandler.Disconnect)
+
+	protected("GET /me", userHandler.GetProfile)
+	protected("PUT /me/preferences", userHandler.UpdatePreferences)
+	protected("PUT /me/timezone", userHandler.UpdateTimezone)
+	protected("PUT /me/password", userHandler.ChangePassword)
+	protected("POST /me/spotify/import", spotifyHandler.StartLibraryImport)
+	protected("GET /me/spotify/import", spotifyHandler.GetLibraryImport)
+	protected("GET /me/spotify/devices", spotifyHandler.GetDevices)
+	protected("GET /me/spotify/player", spotifyHandler.GetPlayback)
+	protected("POST /me/spotify/player/queue", spotifyHandler.QueueTracks)
+
+	protected("GET /recommendations", recommendationHandler.GetRecommendationHistory)
+	protected("POST /recommendations", recommendationHandler.CreateRecommendation)
+	protected("GET /recommendations/{id}", recommendationHandler.GetRecommendation)
+	protected("POST /recommendations/{id}/tracks/{trackID}/feedback", recommendationHandler.SubmitTrackFeedback)
+	protected("POST /recommendations/{id}/play", spotifyHandler.PlayRecommendation)
+
+	public("GET /playlists/public", playlistHandler.GetPublicPlaylists)
+	protected("GET /playlists", playlistHandler.GetUserPlaylists)
+	protected("POST /playlists", playlistHandler.CreatePlaylist)
+	protected("POST /playlists/from-recommendation", playlistHandler.CreateFromRecommendation)
+	protected("GET /playlists/{id}", playlistHandler.GetPlaylist)
+	protected("PUT /playlists/{id}", playlistHandler.UpdatePlaylist)
+	protected("DELETE /playlists/{id}", playlistHandler.DeletePlaylist)
+	protected("GET /playlists/{id}/tracks", playlistHandler.GetPlaylistTracks)
+	protected("POST /playlists/{id}/tracks", playlistHandler.AddTrack)
+	protected("DELETE /playlists/{id}/tracks/{trackID}", playlistHandler.RemoveTrack)
+	protected("POST /playlists/{id}/export/spotify", playlistHandler.ExportToSpotify)
+
+	return mux
+}
+
+// route prefixes the path part of a "METHOD /path" pattern with the API version.
+func route(pattern string) string {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		return apiPrefix + pattern
+	}
+	return method + " " + apiPrefix + path
+}